@@ -1,21 +1,32 @@
 package cmd
 
-import "github.com/urfave/cli/v3"
+import (
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
 
 const (
 	FlagAPIToken                                        = "api-token"
 	FlagBackstageURL                                    = "backstage-url"
 	FlagBackstageToken                                  = "backstage-token"
 	FlagCommitSHA                                       = "commit"
+	FlagConfigCacheSize                                 = "config-cache-size"
+	FlagConfigCacheTTL                                  = "config-cache-ttl"
 	FlagConfigFile                                      = "config"
 	FlagDryRun                                          = "dry-run"
+	FlagDumpContext                                     = "dump-context"
+	FlagFromContext                                     = "from-context"
 	FlagGlobalConfigFile                                = "global-config"
+	FlagHistoryFile                                     = "history-file"
 	FlagMergeRequestID                                  = "id"
+	FlagOutOfOfficeFile                                 = "out-of-office-file"
 	FlagSCMBaseURL                                      = "base-url"
 	FlagSCMProject                                      = "project"
 	FlagServerListenHost                                = "listen-host"
 	FlagServerListenPort                                = "listen-port"
 	FlagServerTimeout                                   = "timeout"
+	FlagStateStore                                      = "state-store"
 	FlagUpdatePipeline                                  = "update-pipeline"
 	FlagUpdatePipelineURL                               = "update-pipeline-url"
 	FlagPeriodicEvaluationInterval                      = "periodic-evaluation-interval"
@@ -41,4 +52,53 @@ var (
 			"BACKSTAGE_TOKEN", // Backstage catalog integration
 		),
 	}
+	StringFlagOutOfOfficeFile = &cli.StringFlag{
+		Name:  FlagOutOfOfficeFile,
+		Usage: "Path to a YAML or iCal (.ics) file with reviewer absences, reviewers who are out of office are never assigned",
+		Sources: cli.EnvVars(
+			"SCM_ENGINE_OUT_OF_OFFICE_FILE",
+		),
+	}
+	StringFlagStateStore = &cli.StringFlag{
+		Name:  FlagStateStore,
+		Usage: "Where to persist state between evaluations, either 'memory' or 'bolt://<path>'",
+		Value: "memory",
+		Sources: cli.EnvVars(
+			"SCM_ENGINE_STATE_STORE",
+		),
+	}
+	StringFlagHistoryFile = &cli.StringFlag{
+		Name:      FlagHistoryFile,
+		Usage:     "Path to a JSON Lines file where the outcome of every evaluation is appended, query it with 'scm-engine history'",
+		TakesFile: true,
+		Sources: cli.EnvVars(
+			"SCM_ENGINE_HISTORY_FILE",
+		),
+	}
+	DurationFlagConfigCacheTTL = &cli.DurationFlag{
+		Name:  FlagConfigCacheTTL,
+		Usage: "How long configuration and 'include' files read from the API are cached between evaluations, '0' disables the cache",
+		Value: 5 * time.Minute,
+		Sources: cli.EnvVars(
+			"SCM_ENGINE_CONFIG_CACHE_TTL",
+		),
+	}
+	IntFlagConfigCacheSize = &cli.IntFlag{
+		Name:  FlagConfigCacheSize,
+		Usage: "The maximum size (in bytes) of the configuration and 'include' files cached between evaluations",
+		Value: 32 << 20,
+		Sources: cli.EnvVars(
+			"SCM_ENGINE_CONFIG_CACHE_SIZE",
+		),
+	}
+	StringFlagDumpContext = &cli.StringFlag{
+		Name:      FlagDumpContext,
+		Usage:     "Write the evaluation context fetched from the API to this JSON file, so it can be evaluated offline with --from-context",
+		TakesFile: true,
+	}
+	StringFlagFromContext = &cli.StringFlag{
+		Name:      FlagFromContext,
+		Usage:     "Evaluate the configuration against an evaluation context written by --dump-context, without any network access, and print the outcome",
+		TakesFile: true,
+	}
 )
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/ruletest"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// dumpContext writes the evaluation context as JSON, so it can later be evaluated with --from-context
+func dumpContext(path string, evalContext scm.EvalContext) error {
+	raw, err := json.MarshalIndent(evalContext, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode evaluation context: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not write evaluation context: %w", err)
+	}
+
+	return nil
+}
+
+// evaluateSnapshot evaluates the configuration against an evaluation context snapshot without any network access,
+// and writes the labels and actions that would have been applied.
+//
+// Remote 'include' files can't be loaded offline, so only the local (and global) configuration is used
+func evaluateSnapshot(ctx context.Context, cfg *config.Config, path string, w io.Writer) error {
+	evalContext, err := ruletest.LoadContext(state.Provider(ctx), path)
+	if err != nil {
+		return err
+	}
+
+	if globalConfig := config.GlobalConfigFromContext(ctx); globalConfig != nil {
+		cfg = globalConfig.Merge(cfg)
+	}
+
+	if len(cfg.Includes) != 0 {
+		slogctx.Warn(ctx, "Configuration file contains 'include' settings, those can't be loaded offline and will be ignored")
+	}
+
+	cfg.ApplyDisable(ctx)
+
+	if err := cfg.Lint(ctx, evalContext); err != nil {
+		return fmt.Errorf("Configuration failed validation: %w", err)
+	}
+
+	ctx = config.WithConfig(ctx, cfg)
+
+	evalContext.SetContext(ctx)
+
+	labels, actions, err := cfg.Evaluate(ctx, evalContext)
+	if err != nil {
+		return err
+	}
+
+	add, remove := labelChanges(evalContext.GetLabels(), labels)
+
+	fmt.Fprintln(w, "Labels:")
+
+	for _, label := range labels {
+		switch {
+		case slices.Contains(add, label.Name):
+			fmt.Fprintf(w, "  + %s\n", label.Name)
+
+		case slices.Contains(remove, label.Name):
+			fmt.Fprintf(w, "  - %s\n", label.Name)
+
+		case label.Matched:
+			fmt.Fprintf(w, "    %s\n", label.Name)
+		}
+	}
+
+	fmt.Fprintln(w, "Actions:")
+
+	for _, action := range actions {
+		// Mirror runActions, only the first action within a group is applied
+		if evalContext.HasExecutedActionGroup(action.Group) {
+			fmt.Fprintf(w, "  %s: skipped, already executed another action within group '%s'\n", action.Name, action.Group)
+
+			continue
+		}
+
+		evalContext.TrackActionGroupExecution(action.Group)
+
+		steps := make([]string, 0, len(action.Steps()))
+
+		for _, step := range action.Steps() {
+			name, _ := step.RequiredString("action")
+			steps = append(steps, name)
+		}
+
+		name := action.Name
+		if action.Branch() == "else" {
+			name += " (else)"
+		}
+
+		fmt.Fprintf(w, "  %s: %s\n", name, strings.Join(steps, ", "))
+	}
+
+	return nil
+}
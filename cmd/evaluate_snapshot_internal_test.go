@@ -0,0 +1,99 @@
+//nolint:testpackage // dumpContext and evaluateSnapshot are unexported, and they decide what --dump-context and --from-context do
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "context.json")
+
+	// The snapshot must not carry the Go context along
+	snapshot := &gitlab.Context{
+		MergeRequest: &gitlab.ContextMergeRequest{
+			Title:  "WIP: fix the thing",
+			Labels: []gitlab.ContextLabel{{Title: "stale"}, {Title: "keep"}},
+		},
+		Context: t.Context(),
+	}
+
+	require.NoError(t, dumpContext(path, snapshot))
+
+	cfg, err := config.ParseFileString(`
+label:
+  - name: wip
+    script: merge_request.title startsWith "WIP"
+  - name: stale
+    script: "false"
+  - name: keep
+    script: "true"
+
+actions:
+  - name: comment on WIP
+    group: wip
+    if: merge_request.title startsWith "WIP"
+    then:
+      - action: comment
+        message: Please remove WIP
+      - action: add_label
+        label: wip
+  - name: close WIP
+    group: wip
+    if: "true"
+    then:
+      - action: close
+  - name: welcome
+    if: merge_request.title startsWith "WIP"
+    then:
+      - action: approve
+    else:
+      - action: comment
+        message: Thanks!
+`)
+	require.NoError(t, err)
+
+	ctx := state.WithProvider(t.Context(), "gitlab")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+	ctx = state.WithDryRun(ctx, true)
+
+	var out bytes.Buffer
+
+	require.NoError(t, evaluateSnapshot(ctx, cfg, path, &out))
+	require.Equal(t, `Labels:
+  + wip
+  - stale
+    keep
+Actions:
+  comment on WIP: comment, add_label
+  close WIP: skipped, already executed another action within group 'wip'
+  welcome: approve
+`, out.String())
+}
+
+func TestEvaluateSnapshot_rejectsInvalidSnapshots(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithProvider(t.Context(), "gitlab")
+
+	err := evaluateSnapshot(ctx, &config.Config{}, filepath.Join(t.TempDir(), "missing.json"), &bytes.Buffer{})
+	require.ErrorContains(t, err, "could not read evaluation context")
+
+	path := filepath.Join(t.TempDir(), "context.json")
+	require.NoError(t, dumpContext(path, &gitlab.Context{}))
+
+	err = evaluateSnapshot(ctx, &config.Config{}, path, &bytes.Buffer{})
+	require.EqualError(t, err, "the context snapshot does not contain a merge request")
+
+	err = evaluateSnapshot(state.WithProvider(t.Context(), "bitbucket"), &config.Config{}, path, &bytes.Buffer{})
+	require.EqualError(t, err, `unknown provider "bitbucket" - we only support 'github' and 'gitlab'`)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/jippi/scm-engine/pkg/state"
 	"github.com/urfave/cli/v3"
@@ -11,7 +12,12 @@ var GitHub = &cli.Command{
 	Name:  "github",
 	Usage: "GitHub related commands",
 	Before: func(ctx context.Context, cCtx *cli.Command) (context.Context, error) {
-		return state.WithProvider(ctx, "github"), nil
+		ctx = state.WithBaseURL(ctx, cCtx.String(FlagSCMBaseURL))
+		ctx = state.WithProvider(ctx, "github")
+		ctx = state.WithToken(ctx, cCtx.String(FlagAPIToken))
+		ctx = state.WithGlobalConfigFilePath(ctx, cCtx.String(FlagGlobalConfigFile))
+
+		return ctx, nil
 	},
 	Flags: []cli.Flag{
 		&cli.StringFlag{
@@ -29,6 +35,12 @@ var GitHub = &cli.Command{
 				"SCM_ENGINE_BASE_URL", // SCM Engine Native
 			),
 		},
+		&cli.StringFlag{
+			Name:    FlagGlobalConfigFile,
+			Usage:   "Path to a global configuration file. Any repository specific configuration will be merged on top of the global configuration",
+			Value:   "",
+			Sources: cli.EnvVars("SCM_ENGINE_GLOBAL_CONFIG_FILE"),
+		},
 	},
 	Commands: []*cli.Command{
 		{
@@ -59,6 +71,73 @@ var GitHub = &cli.Command{
 						"GITHUB_SHA", // GitHub Actions
 					),
 				},
+				StringFlagBackstageURL,
+				StringFlagBackstageToken,
+				StringFlagOutOfOfficeFile,
+				StringFlagStateStore,
+				StringFlagHistoryFile,
+				StringFlagDumpContext,
+				StringFlagFromContext,
+			},
+		},
+		{
+			Name:   "server",
+			Usage:  "Start HTTP server for webhook event driven usage",
+			Action: GitHubServer,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagWebhookSecret,
+					Usage:   "Used to validate received payloads. GitHub signs the payload with it and sends the signature in the X-Hub-Signature-256 HTTP header",
+					Sources: cli.EnvVars("SCM_ENGINE_WEBHOOK_SECRET"),
+				},
+				&cli.StringFlag{
+					Name:    FlagServerListenHost,
+					Usage:   "IP that the HTTP server should listen on",
+					Value:   "0.0.0.0",
+					Sources: cli.EnvVars("SCM_ENGINE_LISTEN_ADDR"),
+				},
+				&cli.IntFlag{
+					Name:  FlagServerListenPort,
+					Usage: "Port that the HTTP server should listen on",
+					Value: 3000,
+					Sources: cli.EnvVars(
+						"SCM_ENGINE_LISTEN_PORT",
+						"PORT",
+					),
+				},
+				&cli.DurationFlag{
+					Name:    FlagServerTimeout,
+					Usage:   "Timeout for webhook requests",
+					Value:   5 * time.Second,
+					Sources: cli.EnvVars("SCM_ENGINE_TIMEOUT"),
+				},
+				&cli.DurationFlag{
+					Name:    FlagPeriodicEvaluationInterval,
+					Usage:   "(Optional) Frequency of which to evaluate all Pull Requests regardless of user activity",
+					Sources: cli.EnvVars("SCM_ENGINE_PERIODIC_EVALUATION_INTERVAL"),
+				},
+				&cli.StringSliceFlag{
+					Name:    FlagPeriodicEvaluationIgnoreMergeRequestsWithLabel,
+					Usage:   "(Optional) Ignore PR with these labels",
+					Sources: cli.EnvVars("SCM_ENGINE_PERIODIC_EVALUATION_IGNORE_MR_WITH_LABELS"),
+				},
+				&cli.StringSliceFlag{
+					Name:    FlagPeriodicEvaluationRequireMergeRequestsWithLabel,
+					Usage:   "(Optional) Only process PR with these labels",
+					Sources: cli.EnvVars("SCM_ENGINE_PERIODIC_EVALUATION_REQUIRE_MR_WITH_LABELS"),
+				},
+				&cli.StringSliceFlag{
+					Name:    FlagPeriodicEvaluationOnlyProjectsWithTopics,
+					Usage:   "(Optional) Only evaluate repositories with these topics",
+					Sources: cli.EnvVars("SCM_ENGINE_PERIODIC_EVALUATION_REQUIRE_PROJECT_TOPICS"),
+				},
+				StringFlagBackstageURL,
+				StringFlagBackstageToken,
+				StringFlagOutOfOfficeFile,
+				StringFlagStateStore,
+				StringFlagHistoryFile,
+				DurationFlagConfigCacheTTL,
+				IntFlagConfigCacheSize,
 			},
 		},
 	},
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/history"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/cache"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/urfave/cli/v3"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+func GitHubServer(ctx context.Context, cCtx *cli.Command) error {
+	var wg sync.WaitGroup
+
+	// Setup context configuration
+	ctx = state.WithConfigFilePath(ctx, cCtx.String(FlagConfigFile))
+
+	// Optional Backstage catalog integration
+	ctx = state.WithBackstageURL(ctx, cCtx.String(FlagBackstageURL))
+	ctx = state.WithBackstageToken(ctx, cCtx.String(FlagBackstageToken))
+
+	// Optional reviewer availability
+	ctx = state.WithOutOfOfficeFilePath(ctx, cCtx.String(FlagOutOfOfficeFile))
+
+	// Persisted state between evaluations
+	store, err := state.OpenStore(cCtx.String(FlagStateStore))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx = state.WithStore(ctx, store)
+
+	// Optional evaluation history
+	if path := cCtx.String(FlagHistoryFile); len(path) > 0 {
+		ctx = history.WithLog(ctx, history.NewLog(path))
+	}
+
+	// Optional cache of configuration files, shared between evaluations
+	if ttl := cCtx.Duration(FlagConfigCacheTTL); ttl > 0 {
+		configCache := cache.New(ttl, cCtx.Int(FlagConfigCacheSize))
+		defer func() {
+			slogctx.Info(ctx, "Remote configuration cache statistics", slog.Any("cache", configCache.Stats()))
+		}()
+
+		ctx = cache.WithCache(ctx, configCache)
+	}
+
+	// Add logging context key/value pairs
+	ctx = slogctx.With(ctx, slog.String("github_url", cCtx.String(FlagSCMBaseURL)))
+	ctx = slogctx.With(ctx, slog.Duration("server_timeout", cCtx.Duration(FlagServerTimeout)))
+
+	//
+	// Setup global config if present
+	//
+	if state.GlobalConfigFilePath(ctx) != "" {
+		globalCfg, err := config.LoadFile(state.GlobalConfigFilePath(ctx))
+		if err != nil {
+			return err
+		}
+
+		ctx = config.WithGlobalConfig(ctx, globalCfg)
+	}
+
+	//
+	// Setup periodic evaluation logic
+	//
+
+	slogctx.Info(ctx, "Starting periodic evaluation server")
+
+	filter := scm.MergeRequestListFilters{
+		IgnoreMergeRequestWithLabels: cCtx.StringSlice(FlagPeriodicEvaluationIgnoreMergeRequestsWithLabel),
+		OnlyMergeRequestsWithLabels:  cCtx.StringSlice(FlagPeriodicEvaluationRequireMergeRequestsWithLabel),
+		OnlyProjectsWithTopics:       cCtx.StringSlice(FlagPeriodicEvaluationOnlyProjectsWithTopics),
+		SCMConfigurationFilePath:     cCtx.String(FlagConfigFile),
+	}
+
+	evalCtx, stopPeriodicEvaluation := context.WithCancel(ctx)
+	startPeriodicEvaluation(evalCtx, cCtx.Duration(FlagPeriodicEvaluationInterval), filter, &wg)
+
+	//
+	// Setup HTTP server
+	//
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /github", GitHubWebhookHandler(ctx, cCtx.String(FlagWebhookSecret)))
+
+	return serve(ctx, cCtx, mux, stopPeriodicEvaluation, &wg)
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/hashicorp/go-multierror"
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+func GitHubWebhookHandler(ctx context.Context, webhookSecret string) http.HandlerFunc {
+	// Initialize GitHub client
+	client, err := getClient(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Read the POST body of the request
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			errHandler(ctx, w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		// Check if the webhook secret is set (and if the payload is signed with it)
+		//
+		// Unlike GitLab, GitHub never sends the secret itself, but a HMAC-SHA256 signature of the POST body
+		if len(webhookSecret) > 0 {
+			theirSignature := r.Header.Get(go_github.SHA256SignatureHeader)
+			if !strings.HasPrefix(theirSignature, "sha256=") || go_github.ValidateSignature(theirSignature, body, []byte(webhookSecret)) != nil {
+				errHandler(ctx, w, http.StatusForbidden, errors.New("Missing or invalid X-Hub-Signature-256 header"))
+
+				return
+			}
+		}
+
+		// Validate content type
+		if r.Header.Get("Content-Type") != "application/json" {
+			errHandler(ctx, w, http.StatusNotAcceptable, errors.New("The request is not using Content-Type: application/json"))
+
+			return
+		}
+
+		// Ensure we have content in the POST body
+		if len(body) == 0 {
+			errHandler(ctx, w, http.StatusBadRequest, errors.New("The POST body is empty; expected a JSON payload"))
+
+			return
+		}
+
+		eventType := go_github.WebHookType(r)
+		ctx = slogctx.With(ctx, slog.String("event_type", eventType))
+
+		switch eventType {
+		// Sent by GitHub when the webhook is created, there is nothing to evaluate
+		case "ping":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+
+			return
+
+		case "pull_request", "pull_request_review", "issue_comment", "push":
+
+		default:
+			errHandler(ctx, w, http.StatusInternalServerError, fmt.Errorf("unknown event type: %s", eventType))
+
+			return
+		}
+
+		// Decode request payload
+		payload, err := go_github.ParseWebHook(eventType, body)
+		if err != nil {
+			errHandler(ctx, w, http.StatusBadRequest, fmt.Errorf("could not decode POST body into Payload struct: %w", err))
+
+			return
+		}
+
+		// Grab event specific information
+		var (
+			project      string
+			pullRequests []scm.ListMergeRequest
+			skipReason   string
+		)
+
+		switch event := payload.(type) {
+		case *go_github.PullRequestEvent:
+			project = event.GetRepo().GetFullName()
+			pullRequests = append(pullRequests, scm.ListMergeRequest{
+				ID:  strconv.Itoa(event.GetNumber()),
+				SHA: event.GetPullRequest().GetHead().GetSHA(),
+			})
+
+		case *go_github.PullRequestReviewEvent:
+			project = event.GetRepo().GetFullName()
+			pullRequests = append(pullRequests, scm.ListMergeRequest{
+				ID:  strconv.Itoa(event.GetPullRequest().GetNumber()),
+				SHA: event.GetPullRequest().GetHead().GetSHA(),
+			})
+
+		case *go_github.IssueCommentEvent:
+			// GitHub sends the same event for comments on both Issues and Pull Requests
+			if !event.GetIssue().IsPullRequest() {
+				skipReason = "comment is not on a Pull Request"
+
+				break
+			}
+
+			// The comment event does not include the Pull Request HEAD commit, the
+			// config file will be read from the Pull Request HEAD instead
+			project = event.GetRepo().GetFullName()
+			pullRequests = append(pullRequests, scm.ListMergeRequest{
+				ID: strconv.Itoa(event.GetIssue().GetNumber()),
+			})
+
+		case *go_github.PushEvent:
+			branch, isBranch := strings.CutPrefix(event.GetRef(), "refs/heads/")
+			if !isBranch || event.GetDeleted() {
+				skipReason = "push is not to an existing branch"
+
+				break
+			}
+
+			project = event.GetRepo().GetFullName()
+
+			// A push can update any number of Pull Requests using the branch
+			pullRequests, err = client.MergeRequests().List(state.WithProjectID(ctx, project), &scm.ListMergeRequestsOptions{State: "opened", First: 100, SourceBranch: branch})
+			if err != nil {
+				errHandler(ctx, w, http.StatusInternalServerError, err)
+
+				return
+			}
+		}
+
+		if len(skipReason) > 0 {
+			slogctx.Info(ctx, "Skipping webhook event", slog.String("reason", skipReason))
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+
+			return
+		}
+
+		// Initialize context
+		ctx = state.WithProjectID(ctx, project)
+
+		slogctx.Info(ctx, "POST /github webhook", slog.Int("number_of_pull_requests", len(pullRequests)))
+
+		// Decode request payload into 'any' so we have all the details
+		var fullEventPayload any
+		if err := json.NewDecoder(bytes.NewReader(body)).Decode(&fullEventPayload); err != nil {
+			errHandler(ctx, w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		var errs error
+
+		for _, pullRequest := range pullRequests {
+			// Build context for rest of the pipeline
+			ctx := state.WithCommitSHA(ctx, pullRequest.SHA)
+			ctx = state.WithMergeRequestID(ctx, pullRequest.ID)
+
+			// Check if there exists scm-config file in the repo before moving forward
+			file, err := client.MergeRequests().GetRemoteConfig(ctx, state.ConfigFilePath(ctx), state.CommitSHA(ctx))
+			// only error when global config is not set
+			if err != nil && state.GlobalConfigFilePath(ctx) == "" {
+				errs = multierror.Append(errs, err)
+
+				continue
+			}
+
+			// Try to parse the config file
+			//
+			// In case of a parse error cfg remains "nil" and ProcessMR will try to read-and-parse it
+			// (but obviously also fail) and report the error
+			var cfg *config.Config
+			if file != nil { // file could be nil if no scm-config file is found when global config is set
+				cfg, _ = config.ParseFile(file)
+			} else {
+				// avoid trying to read-and-parse again if global config is set
+				cfg = config.GlobalConfigFromContext(ctx)
+			}
+
+			// Process the PR
+			if err := ProcessMR(ctx, client, cfg, fullEventPayload); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+
+		if errs != nil {
+			errHandler(ctx, w, http.StatusOK, errs)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
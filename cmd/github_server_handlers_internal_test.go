@@ -0,0 +1,258 @@
+//nolint:testpackage // the webhook handler is built from unexported helpers and package level state
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitHubWebhook builds the handler against a local stand-in for GitHub, so a
+// payload that gets past validation fails on a controlled 404 instead of
+// reaching the network. Listing Pull Requests returns an empty list.
+func newGitHubWebhook(t *testing.T, secret string) func(string, map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/pulls") {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[]`))
+
+			return
+		}
+
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithProvider(t.Context(), "github")
+	ctx = state.WithToken(ctx, "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithBackstageURL(ctx, "")
+	ctx = state.WithBackstageToken(ctx, "")
+	ctx = state.WithOutOfOfficeFilePath(ctx, "")
+	ctx = state.WithConfigFilePath(ctx, ".scm-engine.yml")
+	ctx = state.WithGlobalConfigFilePath(ctx, "")
+	ctx = state.WithDryRun(ctx, true)
+	ctx = state.WithUpdatePipeline(ctx, false, "")
+
+	handler := GitHubWebhookHandler(ctx, secret)
+
+	return func(body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/github", strings.NewReader(body))
+
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler(recorder, req)
+
+		return recorder
+	}
+}
+
+func githubHeaders(event string) map[string]string {
+	return map[string]string{"Content-Type": "application/json", "X-GitHub-Event": event}
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGitHubWebhookHandler_rejectsBadSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "no signature header", signature: ""},
+		{name: "signed with another secret", signature: sign("nope", `{}`)},
+		{name: "not a sha256 signature", signature: "sha1=0000000000000000000000000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers := githubHeaders("ping")
+			if tt.signature != "" {
+				headers["X-Hub-Signature-256"] = tt.signature
+			}
+
+			recorder := newGitHubWebhook(t, "expected-secret")(`{}`, headers)
+
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+			require.Contains(t, recorder.Body.String(), "Missing or invalid X-Hub-Signature-256")
+		})
+	}
+}
+
+func TestGitHubWebhookHandler_acceptsValidSignature(t *testing.T) {
+	t.Parallel()
+
+	headers := githubHeaders("ping")
+	headers["X-Hub-Signature-256"] = sign("expected-secret", `{}`)
+
+	recorder := newGitHubWebhook(t, "expected-secret")(`{}`, headers)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+// With no secret configured the signature header must not be required at all.
+func TestGitHubWebhookHandler_secretIsOptional(t *testing.T) {
+	t.Parallel()
+
+	recorder := newGitHubWebhook(t, "")(`{}`, githubHeaders("ping"))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestGitHubWebhookHandler_requiresJSONContentType(t *testing.T) {
+	t.Parallel()
+
+	recorder := newGitHubWebhook(t, "")(`{}`, map[string]string{
+		"Content-Type":   "application/x-www-form-urlencoded",
+		"X-GitHub-Event": "ping",
+	})
+
+	require.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "Content-Type: application/json")
+}
+
+func TestGitHubWebhookHandler_rejectsEmptyBody(t *testing.T) {
+	t.Parallel()
+
+	recorder := newGitHubWebhook(t, "")("", githubHeaders("pull_request"))
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "The POST body is empty")
+}
+
+func TestGitHubWebhookHandler_rejectsMalformedJSON(t *testing.T) {
+	t.Parallel()
+
+	recorder := newGitHubWebhook(t, "")(`{"broken":`, githubHeaders("pull_request"))
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "could not decode POST body")
+}
+
+func TestGitHubWebhookHandler_rejectsUnknownEventType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		event string
+	}{
+		{name: "unsupported event", event: "workflow_run"},
+		{name: "no event header at all", event: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := newGitHubWebhook(t, "")(`{}`, githubHeaders(tt.event))
+
+			require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			require.Contains(t, recorder.Body.String(), "unknown event type")
+		})
+	}
+}
+
+// Events that can never map to a Pull Request are acknowledged without any evaluation.
+func TestGitHubWebhookHandler_skipsEventsWithoutPullRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		event string
+		body  string
+	}{
+		{
+			name:  "comment on an issue",
+			event: "issue_comment",
+			body:  `{"repository":{"full_name":"jippi/scm-engine"},"issue":{"number":42}}`,
+		},
+		{
+			name:  "push of a tag",
+			event: "push",
+			body:  `{"ref":"refs/tags/v1.0.0","after":"abc123","repository":{"full_name":"jippi/scm-engine"}}`,
+		},
+		{
+			name:  "deleted branch",
+			event: "push",
+			body:  `{"ref":"refs/heads/feature","deleted":true,"repository":{"full_name":"jippi/scm-engine"}}`,
+		},
+		{
+			name:  "push without open Pull Requests",
+			event: "push",
+			body:  `{"ref":"refs/heads/feature","after":"abc123","repository":{"full_name":"jippi/scm-engine"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := newGitHubWebhook(t, "")(tt.body, githubHeaders(tt.event))
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "OK", recorder.Body.String())
+		})
+	}
+}
+
+// The supported event types carry the Pull Request number and commit SHA in
+// different places in the payload, so all shapes have to be understood.
+func TestGitHubWebhookHandler_acceptsSupportedEventTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		event string
+		body  string
+	}{
+		{
+			name:  "pull_request event",
+			event: "pull_request",
+			body:  `{"number":42,"repository":{"full_name":"jippi/scm-engine"},"pull_request":{"number":42,"head":{"sha":"abc123"}}}`,
+		},
+		{
+			name:  "pull_request_review event",
+			event: "pull_request_review",
+			body:  `{"repository":{"full_name":"jippi/scm-engine"},"pull_request":{"number":42,"head":{"sha":"abc123"}}}`,
+		},
+		{
+			name:  "issue_comment event",
+			event: "issue_comment",
+			body: `{"repository":{"full_name":"jippi/scm-engine"},` +
+				`"issue":{"number":42,"pull_request":{"url":"https://api.github.com/repos/jippi/scm-engine/pulls/42"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := newGitHubWebhook(t, "")(tt.body, githubHeaders(tt.event))
+
+			// The payload is understood, so the handler gets past validation and
+			// on to fetching the config, which the stand-in answers with a 404.
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Contains(t, recorder.Body.String(), "404 Not Found")
+		})
+	}
+}
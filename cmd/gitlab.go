@@ -97,6 +97,11 @@ var GitLab = &cli.Command{
 				},
 				StringFlagBackstageURL,
 				StringFlagBackstageToken,
+				StringFlagOutOfOfficeFile,
+				StringFlagStateStore,
+				StringFlagHistoryFile,
+				StringFlagDumpContext,
+				StringFlagFromContext,
 			},
 		},
 		{
@@ -170,6 +175,11 @@ var GitLab = &cli.Command{
 				},
 				StringFlagBackstageURL,
 				StringFlagBackstageToken,
+				StringFlagOutOfOfficeFile,
+				StringFlagStateStore,
+				StringFlagHistoryFile,
+				DurationFlagConfigCacheTTL,
+				IntFlagConfigCacheSize,
 			},
 		},
 	},
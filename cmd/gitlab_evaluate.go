@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"time"
 
 	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/history"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
 	"github.com/urfave/cli/v3"
@@ -24,6 +26,23 @@ func Evaluate(ctx context.Context, cCtx *cli.Command) error {
 	ctx = state.WithBackstageURL(ctx, cCtx.String(FlagBackstageURL))
 	ctx = state.WithBackstageToken(ctx, cCtx.String(FlagBackstageToken))
 
+	// Optional reviewer availability
+	ctx = state.WithOutOfOfficeFilePath(ctx, cCtx.String(FlagOutOfOfficeFile))
+
+	// Persisted state between evaluations
+	store, err := state.OpenStore(cCtx.String(FlagStateStore))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx = state.WithStore(ctx, store)
+
+	// Optional evaluation history
+	if path := cCtx.String(FlagHistoryFile); len(path) > 0 {
+		ctx = history.WithLog(ctx, history.NewLog(path))
+	}
+
 	//
 	// Setup global config if present
 	//
@@ -41,6 +60,15 @@ func Evaluate(ctx context.Context, cCtx *cli.Command) error {
 		return err
 	}
 
+	// Evaluate against a recorded context snapshot, without any network access
+	if path := cCtx.String(FlagFromContext); len(path) > 0 {
+		ctx = state.WithMergeRequestID(ctx, cmp.Or(cCtx.String(FlagMergeRequestID), cCtx.Args().First()))
+
+		return evaluateSnapshot(ctx, cfg, path, cCtx.Root().Writer)
+	}
+
+	ctx = state.WithDumpContextFilePath(ctx, cCtx.String(FlagDumpContext))
+
 	client, err := getClient(ctx)
 	if err != nil {
 		return err
@@ -2,19 +2,14 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"log/slog"
-	"net"
 	"net/http"
-	"os"
-	"os/signal"
-	"strconv"
 	"sync"
-	"syscall"
-	"time"
 
 	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/history"
 	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/cache"
 	"github.com/jippi/scm-engine/pkg/state"
 	"github.com/urfave/cli/v3"
 	slogctx "github.com/veqryn/slog-context"
@@ -32,6 +27,33 @@ func Server(ctx context.Context, cCtx *cli.Command) error {
 	ctx = state.WithBackstageURL(ctx, cCtx.String(FlagBackstageURL))
 	ctx = state.WithBackstageToken(ctx, cCtx.String(FlagBackstageToken))
 
+	// Optional reviewer availability
+	ctx = state.WithOutOfOfficeFilePath(ctx, cCtx.String(FlagOutOfOfficeFile))
+
+	// Persisted state between evaluations
+	store, err := state.OpenStore(cCtx.String(FlagStateStore))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx = state.WithStore(ctx, store)
+
+	// Optional evaluation history
+	if path := cCtx.String(FlagHistoryFile); len(path) > 0 {
+		ctx = history.WithLog(ctx, history.NewLog(path))
+	}
+
+	// Optional cache of configuration files, shared between evaluations
+	if ttl := cCtx.Duration(FlagConfigCacheTTL); ttl > 0 {
+		configCache := cache.New(ttl, cCtx.Int(FlagConfigCacheSize))
+		defer func() {
+			slogctx.Info(ctx, "Remote configuration cache statistics", slog.Any("cache", configCache.Stats()))
+		}()
+
+		ctx = cache.WithCache(ctx, configCache)
+	}
+
 	// Add logging context key/value pairs
 	ctx = slogctx.With(ctx, slog.String("gitlab_url", cCtx.String(FlagSCMBaseURL)))
 	ctx = slogctx.With(ctx, slog.Duration("server_timeout", cCtx.Duration(FlagServerTimeout)))
@@ -69,72 +91,8 @@ func Server(ctx context.Context, cCtx *cli.Command) error {
 	// Setup HTTP server
 	//
 
-	// NOTE: FlagServerListenPort is an IntFlag; cli/v3 returns an empty string
-	// from String() for non-string flags, so it must be read as an int.
-	listenAddr := net.JoinHostPort(cCtx.String(FlagServerListenHost), strconv.Itoa(cCtx.Int(FlagServerListenPort)))
-	slogctx.Info(ctx, "Starting HTTP server", slog.String("listen_address", listenAddr))
-
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /_status", GitLabStatusHandler)
 	mux.HandleFunc("POST /gitlab", GitLabWebhookHandler(ctx, cCtx.String(FlagWebhookSecret)))
 
-	server := &http.Server{
-		Addr:         listenAddr,
-		Handler:      mux,
-		ReadTimeout:  cCtx.Duration(FlagServerTimeout),
-		WriteTimeout: cCtx.Duration(FlagServerTimeout),
-		BaseContext: func(l net.Listener) context.Context {
-			return ctx
-		},
-	}
-
-	//
-	// Start HTTP server in a Go routine
-	//
-
-	wg.Add(1) // +1: HTTP Server
-
-	go func() {
-		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-			slogctx.Error(ctx, "HTTP server error", slog.Any("error", err))
-
-			os.Exit(1)
-		}
-
-		slogctx.Info(ctx, "Stopped serving new connections.")
-	}()
-
-	//
-	// Wait for shutdown signals
-	//
-
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
-
-	//
-	// Graceful shutdown logic
-	//
-
-	slogctx.Info(ctx, "Got SIGINT/SIGTERM, starting graceful shutdown.")
-
-	stopPeriodicEvaluation()
-
-	// NOTE: do not use the existing "ctx" since its already cancelled in developer mode if CTRL+C-ing
-	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownRelease()
-
-	if err := server.Shutdown(shutdownCtx); err != nil { //nolint:contextcheck // deliberate, see NOTE above
-		slogctx.Error(ctx, "HTTP shutdown error", slog.Any("error", err))
-	}
-
-	wg.Done() // -1: HTTP Server - shutdown complete
-
-	slogctx.Info(ctx, "Graceful HTTP shutdown complete")
-
-	wg.Wait() // Wait for PeriodicEvaluation to complete
-
-	slogctx.Info(ctx, "Graceful shutdown complete")
-
-	return nil
+	return serve(ctx, cCtx, mux, stopPeriodicEvaluation, &wg)
 }
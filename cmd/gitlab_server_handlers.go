@@ -16,15 +16,6 @@ import (
 	slogctx "github.com/veqryn/slog-context"
 )
 
-func GitLabStatusHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	slogctx.Debug(ctx, "GET /_status")
-
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("scm-engine status: OK\n\nNOTE: this is a static 'OK', no actual checks are being made"))
-}
-
 func GitLabWebhookHandler(ctx context.Context, webhookSecret string) http.HandlerFunc {
 	// Initialize GitLab client
 	client, err := getClient(ctx)
@@ -13,17 +13,6 @@ import (
 
 var jsonHeaders = map[string]string{"Content-Type": "application/json"}
 
-func TestGitLabStatusHandler(t *testing.T) {
-	t.Parallel()
-
-	recorder := httptest.NewRecorder()
-
-	GitLabStatusHandler(recorder, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/_status", nil))
-
-	require.Equal(t, http.StatusOK, recorder.Code)
-	require.Contains(t, recorder.Body.String(), "scm-engine status: OK")
-}
-
 // newWebhook builds the handler against a local stand-in for GitLab, so a
 // payload that gets past validation fails on a controlled 404 instead of
 // reaching the network.
@@ -44,6 +33,7 @@ func newWebhook(t *testing.T, secret string) func(string, map[string]string) *ht
 	ctx = state.WithBaseURL(ctx, upstream.URL)
 	ctx = state.WithBackstageURL(ctx, "")
 	ctx = state.WithBackstageToken(ctx, "")
+	ctx = state.WithOutOfOfficeFilePath(ctx, "")
 	ctx = state.WithConfigFilePath(ctx, ".scm-engine.yml")
 	ctx = state.WithGlobalConfigFilePath(ctx, "")
 	ctx = state.WithDryRun(ctx, true)
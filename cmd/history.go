@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/history"
+	"github.com/urfave/cli/v3"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+var History = &cli.Command{
+	Name:   "history",
+	Usage:  "Show the recorded outcome of past evaluations",
+	Action: ShowHistory,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      FlagHistoryFile,
+			Usage:     "Path to the JSON Lines history file written by 'evaluate' and 'server'",
+			Required:  true,
+			TakesFile: true,
+			Sources:   cli.EnvVars("SCM_ENGINE_HISTORY_FILE"),
+		},
+		&cli.StringFlag{
+			Name:  FlagSCMProject,
+			Usage: "Only show evaluations of this project (example: 'jippi/scm-engine')",
+		},
+		&cli.StringFlag{
+			Name:  FlagMergeRequestID,
+			Usage: "Only show evaluations of this Merge Request / Pull Request ID",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Only show evaluations after this time, either a duration ago (example: '7d') or a date (example: '2026-10-01' or RFC3339)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "Only show evaluations before this time, either a duration ago (example: '1d') or a date (example: '2026-10-01' or RFC3339)",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Only show the most recent evaluations, 0 shows all of them",
+			Value: 50,
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output the raw JSON Lines entries",
+		},
+	},
+}
+
+func ShowHistory(ctx context.Context, cCtx *cli.Command) error {
+	now := time.Now()
+
+	since, err := parseHistoryTime(cCtx.String("since"), now)
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+
+	until, err := parseHistoryTime(cCtx.String("until"), now)
+	if err != nil {
+		return fmt.Errorf("invalid --until value: %w", err)
+	}
+
+	entries, err := history.NewLog(cCtx.String(FlagHistoryFile)).Query(history.Filter{
+		Project:      cCtx.String(FlagSCMProject),
+		MergeRequest: cCtx.String(FlagMergeRequestID),
+		Since:        since,
+		Until:        until,
+		Limit:        int(cCtx.Int("limit")),
+	})
+	if err != nil {
+		return err
+	}
+
+	if cCtx.Bool("json") {
+		encoder := json.NewEncoder(cCtx.Root().Writer)
+
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return writeHistoryTable(cCtx.Root().Writer, entries)
+}
+
+// parseHistoryTime accepts a duration relative to now ("7d"), a date ("2026-10-01") or a RFC3339 timestamp
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	if duration, err := str2duration.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return date, nil
+	}
+
+	stamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("must be a duration (example: '7d'), a date (example: '2026-10-01') or a RFC3339 timestamp")
+	}
+
+	return stamp, nil
+}
+
+func writeHistoryTable(w io.Writer, entries []history.Entry) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "TIME\tEVALUATION\tPROJECT\tID\tDRY RUN\tLABELS\tACTIONS\tERROR")
+
+	for _, entry := range entries {
+		labels := make([]string, 0, len(entry.LabelsAdded)+len(entry.LabelsRemoved))
+
+		for _, label := range entry.LabelsAdded {
+			labels = append(labels, "+"+label)
+		}
+
+		for _, label := range entry.LabelsRemoved {
+			labels = append(labels, "-"+label)
+		}
+
+		actions := make([]string, 0, len(entry.Actions))
+
+		for _, action := range entry.Actions {
+			actions = append(actions, formatHistoryAction(action))
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%t\t%s\t%s\t%s\n",
+			entry.Time.Local().Format(time.DateTime),
+			entry.EvaluationID,
+			entry.Project,
+			entry.MergeRequest,
+			entry.DryRun,
+			orDash(strings.Join(labels, " ")),
+			orDash(strings.Join(actions, "; ")),
+			orDash(entry.Error),
+		)
+	}
+
+	return table.Flush()
+}
+
+// formatHistoryAction renders an action like "close stale [else] (comment [failed], close [skipped])"
+func formatHistoryAction(action history.Action) string {
+	name := action.Name
+	if len(action.Branch) > 0 {
+		name += " [" + action.Branch + "]"
+	}
+
+	steps := make([]string, 0, len(action.Steps))
+
+	for _, step := range action.Steps {
+		if step.Status == history.StepSucceeded {
+			steps = append(steps, step.Action)
+
+			continue
+		}
+
+		steps = append(steps, fmt.Sprintf("%s [%s]", step.Action, step.Status))
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(steps, ", "))
+}
+
+func orDash(value string) string {
+	if len(value) == 0 {
+		return "-"
+	}
+
+	return value
+}
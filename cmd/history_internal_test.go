@@ -0,0 +1,72 @@
+//nolint:testpackage // parseHistoryTime and writeHistoryTable are unexported, and they decide how 'scm-engine history' reads flags and prints entries
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/history"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty means no limit", value: "", want: time.Time{}},
+		{name: "duration with days", value: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		{name: "duration with hours", value: "3h", want: now.Add(-3 * time.Hour)},
+		{name: "date", value: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)},
+		{name: "timestamp", value: "2026-10-01T08:30:00Z", want: time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)},
+		{name: "garbage", value: "last tuesday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseHistoryTime(tt.value, now)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestWriteHistoryTable(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	require.NoError(t, writeHistoryTable(&out, []history.Entry{
+		{
+			EvaluationID:  "abc",
+			Project:       "jippi/scm-engine",
+			MergeRequest:  "42",
+			LabelsAdded:   []string{"stale"},
+			LabelsRemoved: []string{"active"},
+			Actions: []history.Action{
+				{Name: "close stale", Steps: []history.Step{{Action: "comment", Status: history.StepSucceeded}, {Action: "close", Status: history.StepSucceeded}}},
+				{Name: "nag", Branch: "else", Steps: []history.Step{{Action: "comment", Status: history.StepFailed, Error: "boom"}, {Action: "add_label", Status: history.StepSkipped}}},
+			},
+		},
+		{EvaluationID: "def", Project: "jippi/scm-engine", MergeRequest: "43", Error: "boom"},
+	}))
+
+	require.Contains(t, out.String(), "+stale -active")
+	require.Contains(t, out.String(), "close stale (comment, close); nag [else] (comment [failed], add_label [skipped])")
+	require.Contains(t, out.String(), "boom")
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	slogctx.Debug(ctx, "GET /_status")
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("scm-engine status: OK\n\nNOTE: this is a static 'OK', no actual checks are being made"))
+}
+
+// serve runs the HTTP server until SIGINT/SIGTERM is received, and then shuts it down gracefully.
+//
+// stopPeriodicEvaluation is called once the signal is received, and serve will wait for
+// everything tracked by [wg] to complete before returning
+func serve(ctx context.Context, cCtx *cli.Command, mux *http.ServeMux, stopPeriodicEvaluation context.CancelFunc, wg *sync.WaitGroup) error {
+	// NOTE: FlagServerListenPort is an IntFlag; cli/v3 returns an empty string
+	// from String() for non-string flags, so it must be read as an int.
+	listenAddr := net.JoinHostPort(cCtx.String(FlagServerListenHost), strconv.Itoa(cCtx.Int(FlagServerListenPort)))
+	slogctx.Info(ctx, "Starting HTTP server", slog.String("listen_address", listenAddr))
+
+	mux.HandleFunc("GET /_status", StatusHandler)
+
+	server := &http.Server{
+		Addr:         listenAddr,
+		Handler:      mux,
+		ReadTimeout:  cCtx.Duration(FlagServerTimeout),
+		WriteTimeout: cCtx.Duration(FlagServerTimeout),
+		BaseContext: func(l net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	//
+	// Start HTTP server in a Go routine
+	//
+
+	wg.Add(1) // +1: HTTP Server
+
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			slogctx.Error(ctx, "HTTP server error", slog.Any("error", err))
+
+			os.Exit(1)
+		}
+
+		slogctx.Info(ctx, "Stopped serving new connections.")
+	}()
+
+	//
+	// Wait for shutdown signals
+	//
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	//
+	// Graceful shutdown logic
+	//
+
+	slogctx.Info(ctx, "Got SIGINT/SIGTERM, starting graceful shutdown.")
+
+	stopPeriodicEvaluation()
+
+	// NOTE: do not use the existing "ctx" since its already cancelled in developer mode if CTRL+C-ing
+	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownRelease()
+
+	if err := server.Shutdown(shutdownCtx); err != nil { //nolint:contextcheck // deliberate, see NOTE above
+		slogctx.Error(ctx, "HTTP shutdown error", slog.Any("error", err))
+	}
+
+	wg.Done() // -1: HTTP Server - shutdown complete
+
+	slogctx.Info(ctx, "Graceful HTTP shutdown complete")
+
+	wg.Wait() // Wait for PeriodicEvaluation to complete
+
+	slogctx.Info(ctx, "Graceful shutdown complete")
+
+	return nil
+}
@@ -0,0 +1,21 @@
+//nolint:testpackage // the status handler is shared by the unexported server setup
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+
+	StatusHandler(recorder, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/_status", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "scm-engine status: OK")
+}
@@ -40,6 +40,9 @@ func startPeriodicEvaluation(ctx context.Context, interval time.Duration, filter
 		panic(err)
 	}
 
+	// Merge Requests can only opt-out of pipeline updates if they are enabled in the first place (they never are for GitHub)
+	shouldUpdatePipeline, _ := state.ShouldUpdatePipeline(ctx)
+
 	// Configure logger and custom fields
 	ctx = slogctx.With(ctx,
 		slog.Any("periodic_evaluation_filters", filter.AsGraphqlVariables()),
@@ -83,7 +86,7 @@ func startPeriodicEvaluation(ctx context.Context, interval time.Duration, filter
 					ctx = state.WithMergeRequestID(ctx, mergeRequest.MergeRequestID)
 					ctx = state.WithProjectID(ctx, mergeRequest.Project)
 
-					if !mergeRequest.UpdatePipeline {
+					if shouldUpdatePipeline && !mergeRequest.UpdatePipeline {
 						slogctx.Info(ctx, "Disabling CI pipeline commit status updating since the MR HEAD CI pipeline is in a failed state")
 
 						ctx = state.WithUpdatePipeline(ctx, false, "")
@@ -11,8 +11,11 @@ import (
 	"time"
 
 	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/history"
 	"github.com/jippi/scm-engine/pkg/integration/backstage"
+	"github.com/jippi/scm-engine/pkg/integration/outofoffice"
 	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/cache"
 	"github.com/jippi/scm-engine/pkg/scm/github"
 	"github.com/jippi/scm-engine/pkg/scm/gitlab"
 	"github.com/jippi/scm-engine/pkg/state"
@@ -28,16 +31,38 @@ func getClient(ctx context.Context) (scm.Client, error) {
 		slogctx.Warn(ctx, "Backstage client is not available, actions requiring it will be skipped", slog.Any("error", err))
 	}
 
+	var outOfOffice scm.OutOfOfficeProvider
+
+	if path := state.OutOfOfficeFilePath(ctx); len(path) > 0 {
+		outOfOffice, err = outofoffice.NewFileProvider(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var client scm.Client
+
 	switch state.Provider(ctx) {
 	case "github":
-		return github.NewClient(ctx)
+		client, err = github.NewClient(ctx, backstageClient, outOfOffice)
 
 	case "gitlab":
-		return gitlab.NewClient(ctx, backstageClient)
+		client, err = gitlab.NewClient(ctx, backstageClient, outOfOffice)
 
 	default:
 		return nil, fmt.Errorf("unknown provider %q - we only support 'github' and 'gitlab'", state.Provider(ctx))
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Read configuration files through the cache shared between evaluations, if any
+	if configCache := cache.FromContext(ctx); configCache != nil {
+		return cache.NewClient(client, configCache), nil
+	}
+
+	return client, nil
 }
 
 func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event any) (err error) {
@@ -55,6 +80,23 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 
 	defer state.LockForProcessing(ctx)()
 
+	// Record the outcome of the evaluation when leaving this func
+	entry := &history.Entry{
+		EvaluationID: state.EvaluationID(ctx),
+		Time:         state.StartTime(ctx),
+		Provider:     state.Provider(ctx),
+		Project:      state.ProjectID(ctx),
+		MergeRequest: state.MergeRequestID(ctx),
+		CommitSHA:    state.CommitSHA(ctx),
+		DryRun:       state.IsDryRun(ctx),
+	}
+
+	ctx = history.WithEntry(ctx, entry)
+
+	defer func() {
+		recordHistory(ctx, entry, err)
+	}()
+
 	// Stop the pipeline when we leave this func
 	defer func() {
 		if stopErr := client.Stop(ctx, err, allowPipelineFailure); stopErr != nil {
@@ -84,6 +126,15 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 		return nil
 	}
 
+	// Optionally write the evaluation context to disk, so it can be evaluated offline with --from-context
+	if path := state.DumpContextFilePath(ctx); len(path) > 0 {
+		if err := dumpContext(path, evalContext); err != nil {
+			return err
+		}
+
+		slogctx.Info(ctx, "Wrote evaluation context snapshot", slog.String("path", path))
+	}
+
 	// Check if we are allowed to fail the CI pipeline
 	allowPipelineFailure = evalContext.AllowPipelineFailure(ctx)
 
@@ -134,7 +185,7 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 	}
 
 	// Load any remote configuration files
-	if err := cfg.LoadIncludes(ctx, client); err != nil {
+	if err := cfg.LoadIncludes(ctx, config.NewIncludeResolvers(client, configSourceRef)); err != nil {
 		return fmt.Errorf("failed to load 'include' settings: %w", err)
 	}
 
@@ -143,6 +194,7 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 		slogctx.Info(ctx, "Configuration file has a 'dry_run' value, using that in favor of server default")
 
 		ctx = state.WithDryRun(ctx, *cfg.DryRun)
+		entry.DryRun = *cfg.DryRun
 	}
 
 	// Lint the configuration file to catch any misconfigurations
@@ -154,6 +206,12 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 	// If a global config file was set, this overrides the global config with the merged global and repository config
 	ctx = config.WithConfig(ctx, cfg)
 
+	// Compile the definitions once, for the evaluation and the actions applied after it
+	ctx, err = cfg.WithDefinitions(ctx, evalContext)
+	if err != nil {
+		return fmt.Errorf("evaluation failed: %w", err)
+	}
+
 	//
 	// Do the actual context evaluation
 	//
@@ -180,20 +238,7 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 		return err
 	}
 
-	var (
-		add    scm.LabelOptions
-		remove scm.LabelOptions
-	)
-
-	existingLabels := evalContext.GetLabels()
-
-	for _, e := range labels {
-		if e.Matched && !slices.Contains(existingLabels, e.Name) {
-			add = append(add, e.Name)
-		} else if !e.Matched && slices.Contains(existingLabels, e.Name) {
-			remove = append(remove, e.Name)
-		}
-	}
+	add, remove := labelChanges(evalContext.GetLabels(), labels)
 
 	//
 	// Post-evaluation sync of actions
@@ -201,6 +246,9 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 
 	update := &scm.UpdateMergeRequestOptions{}
 
+	entry.LabelsAdded = add
+	entry.LabelsRemoved = remove
+
 	if len(add) > 0 {
 		update.AddLabels = &add
 	}
@@ -224,6 +272,37 @@ func ProcessMR(ctx context.Context, client scm.Client, cfg *config.Config, event
 	return updateMergeRequest(ctx, client, update)
 }
 
+// labelChanges returns the labels to add and remove from the Merge Request, given its existing labels and the evaluated ones
+func labelChanges(existing []string, labels []scm.EvaluationResult) (add, remove scm.LabelOptions) {
+	for _, e := range labels {
+		if e.Matched && !slices.Contains(existing, e.Name) {
+			add = append(add, e.Name)
+		} else if !e.Matched && slices.Contains(existing, e.Name) {
+			remove = append(remove, e.Name)
+		}
+	}
+
+	return add, remove
+}
+
+// recordHistory appends the evaluation outcome to the history log, if one is configured
+func recordHistory(ctx context.Context, entry *history.Entry, err error) {
+	log := history.LogFromContext(ctx)
+	if log == nil {
+		return
+	}
+
+	entry.Duration = time.Since(entry.Time)
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if err := log.Append(*entry); err != nil {
+		slogctx.Error(ctx, "Failed to record evaluation history", slog.Any("error", err))
+	}
+}
+
 func updateMergeRequest(ctx context.Context, client scm.Client, update *scm.UpdateMergeRequestOptions) error {
 	if update == nil || reflect.DeepEqual(update, &scm.UpdateMergeRequestOptions{}) {
 		slogctx.Info(ctx, "No changes to apply to Merge Request")
@@ -263,18 +342,81 @@ func runActions(ctx context.Context, evalContext scm.EvalContext, client scm.Cli
 
 		evalContext.TrackActionGroupExecution(action.Group)
 
-		for _, task := range action.Then {
-			if err := client.ApplyStep(ctx, evalContext, update, task); err != nil {
-				slogctx.Error(ctx, "failed to apply action step", slog.Any("error", err))
+		if err := applyActionSteps(ctx, evalContext, client, update, action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-				return err
-			}
+// applyActionSteps applies the selected steps of an action, honoring the 'on_error' policy of each step.
+//
+// The outcome of every step is recorded in the evaluation history, so it's possible to tell which steps succeeded
+func applyActionSteps(ctx context.Context, evalContext scm.EvalContext, client scm.Client, update *scm.UpdateMergeRequestOptions, action config.Action) error {
+	result := history.Action{Name: action.Name}
+	if action.Branch() == "else" {
+		result.Branch = action.Branch()
+	}
+
+	defer func() {
+		history.EntryFromContext(ctx).AddAction(result)
+	}()
+
+	steps := action.Steps()
+
+	for idx, task := range steps {
+		name, _ := task.RequiredString("action")
+		ctx := slogctx.With(ctx, slog.String("action_step", name))
+
+		err := client.ApplyStep(ctx, evalContext, update, task)
+		if err == nil {
+			result.Steps = append(result.Steps, history.Step{Action: name, Status: history.StepSucceeded})
+
+			continue
+		}
+
+		result.Steps = append(result.Steps, history.Step{Action: name, Status: history.StepFailed, Error: err.Error()})
+
+		// The policy is validated during lint, so fall back to the default on invalid values
+		policy, _ := task.OnError()
+
+		switch policy {
+		case config.OnErrorContinue:
+			slogctx.Warn(ctx, "failed to apply action step, continuing with the next step", slog.Any("error", err))
+
+			continue
+
+		case config.OnErrorSkipAction:
+			slogctx.Warn(ctx, "failed to apply action step, skipping the remaining steps of the action", slog.Any("error", err))
+
+			result.Steps = append(result.Steps, skippedSteps(steps[idx+1:])...)
+
+			return nil
+
+		default:
+			slogctx.Error(ctx, "failed to apply action step", slog.Any("error", err))
+
+			result.Steps = append(result.Steps, skippedSteps(steps[idx+1:])...)
+
+			return err
 		}
 	}
 
 	return nil
 }
 
+func skippedSteps(steps []config.ActionStep) []history.Step {
+	result := make([]history.Step, 0, len(steps))
+
+	for _, step := range steps {
+		name, _ := step.RequiredString("action")
+		result = append(result, history.Step{Action: name, Status: history.StepSkipped})
+	}
+
+	return result
+}
+
 func syncLabels(ctx context.Context, client scm.Client, required []scm.EvaluationResult) error {
 	slogctx.Info(ctx, "Going to sync required labels", slog.Int("number_of_labels", len(required)))
 
@@ -290,7 +432,7 @@ func syncLabels(ctx context.Context, client scm.Client, required []scm.Evaluatio
 
 	// Create
 	for _, label := range required {
-		if _, ok := remoteLabels[label.Name]; ok {
+		if _, ok := remoteLabels[label.Name]; ok || label.Stale {
 			continue
 		}
 
@@ -321,7 +463,7 @@ func syncLabels(ctx context.Context, client scm.Client, required []scm.Evaluatio
 	// Update
 	for _, label := range required {
 		remote, ok := remoteLabels[label.Name]
-		if !ok {
+		if !ok || label.Stale {
 			continue
 		}
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/history"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
 	"github.com/stretchr/testify/require"
@@ -79,6 +80,8 @@ type fakeClient struct {
 
 	appliedSteps []scm.ActionStep
 	applyErr     error
+	// failSteps fails only the steps with the given 'action' name
+	failSteps map[string]error
 }
 
 func newFakeClient() *fakeClient {
@@ -94,6 +97,11 @@ func (c *fakeClient) MergeRequests() scm.MergeRequestClient { return c.mergeRequ
 func (c *fakeClient) ApplyStep(_ context.Context, _ scm.EvalContext, _ *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
 	c.appliedSteps = append(c.appliedSteps, step)
 
+	name, _ := step.RequiredString("action")
+	if err, ok := c.failSteps[name]; ok {
+		return err
+	}
+
 	return c.applyErr
 }
 
@@ -291,6 +299,115 @@ func TestRunActions_stopsOnError(t *testing.T) {
 	require.Len(t, client.appliedSteps, 1, "evaluation must stop at the failing step")
 }
 
+// Applied actions are recorded in the evaluation history, including the step that failed
+func TestRunActions_recordsHistory(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+
+	entry := &history.Entry{}
+	ctx := history.WithEntry(t.Context(), entry)
+
+	actions := config.Actions{
+		{Name: "first", Then: []config.ActionStep{{"action": "comment"}, {"action": "close"}}},
+		{Name: "second", Group: "stale", Then: []config.ActionStep{{"action": "approve"}}},
+		{Name: "third", Group: "stale", Then: []config.ActionStep{{"action": "approve"}}},
+	}
+
+	require.NoError(t, runActions(ctx, newEvalContextStub(), client, &scm.UpdateMergeRequestOptions{}, actions))
+	require.Equal(t, []history.Action{
+		{Name: "first", Steps: []history.Step{{Action: "comment", Status: history.StepSucceeded}, {Action: "close", Status: history.StepSucceeded}}},
+		{Name: "second", Steps: []history.Step{{Action: "approve", Status: history.StepSucceeded}}},
+	}, entry.Actions)
+
+	client.applyErr = errors.New("step failed")
+	entry.Actions = nil
+
+	require.Error(t, runActions(ctx, newEvalContextStub(), client, &scm.UpdateMergeRequestOptions{}, actions[:1]))
+	require.Equal(t, []history.Action{
+		{Name: "first", Steps: []history.Step{
+			{Action: "comment", Status: history.StepFailed, Error: "step failed"},
+			{Action: "close", Status: history.StepSkipped},
+		}},
+	}, entry.Actions)
+}
+
+// A flaky step with 'on_error' set must not cancel the rest of the evaluation
+func TestRunActions_onError(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	client.failSteps = map[string]error{"comment": errors.New("comment failed")}
+
+	entry := &history.Entry{}
+	ctx := history.WithEntry(t.Context(), entry)
+
+	actions := config.Actions{
+		{Name: "continue", Then: []config.ActionStep{{"action": "comment", "on_error": "continue"}, {"action": "add_label"}}},
+		{Name: "skip", Then: []config.ActionStep{{"action": "comment", "on_error": "skip_action"}, {"action": "close"}}},
+		{Name: "fail", Then: []config.ActionStep{{"action": "comment"}, {"action": "approve"}}},
+		{Name: "never", Then: []config.ActionStep{{"action": "reopen"}}},
+	}
+
+	require.ErrorContains(t,
+		runActions(ctx, newEvalContextStub(), client, &scm.UpdateMergeRequestOptions{}, actions),
+		"comment failed")
+
+	require.Equal(t, []history.Action{
+		{Name: "continue", Steps: []history.Step{
+			{Action: "comment", Status: history.StepFailed, Error: "comment failed"},
+			{Action: "add_label", Status: history.StepSucceeded},
+		}},
+		{Name: "skip", Steps: []history.Step{
+			{Action: "comment", Status: history.StepFailed, Error: "comment failed"},
+			{Action: "close", Status: history.StepSkipped},
+		}},
+		{Name: "fail", Steps: []history.Step{
+			{Action: "comment", Status: history.StepFailed, Error: "comment failed"},
+			{Action: "approve", Status: history.StepSkipped},
+		}},
+	}, entry.Actions)
+
+	require.Len(t, client.appliedSteps, 4, "the steps after a failure must not be applied unless 'on_error: continue'")
+}
+
+// Actions that evaluated negatively apply their 'else' steps
+func TestRunActions_elseBranch(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+
+	entry := &history.Entry{}
+	ctx := history.WithEntry(t.Context(), entry)
+
+	cfg, err := config.ParseFileString(`
+actions:
+  - name: welcome
+    if: "false"
+    then:
+      - action: approve
+    else:
+      - action: comment
+        message: Thanks!
+  - name: ignored
+    if: "false"
+    then:
+      - action: close
+`)
+	require.NoError(t, err)
+
+	evalContext := newEvalContextStub()
+
+	actions, err := cfg.Actions.Evaluate(ctx, evalContext)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+
+	require.NoError(t, runActions(ctx, evalContext, client, &scm.UpdateMergeRequestOptions{}, actions))
+	require.Equal(t, []history.Action{
+		{Name: "welcome", Branch: "else", Steps: []history.Step{{Action: "comment", Status: history.StepSucceeded}}},
+	}, entry.Actions)
+}
+
 func TestSyncLabels_createsMissingLabels(t *testing.T) {
 	t.Parallel()
 
@@ -341,6 +458,24 @@ func TestSyncLabels_updatesChangedLabels(t *testing.T) {
 	require.Equal(t, []string{"bug"}, client.labels.updated)
 }
 
+// Stale owned labels are only removed from the Merge Request, the label itself is left alone
+func TestSyncLabels_skipsStaleLabels(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	client.labels.existing = []*scm.Label{{Name: "component/legacy", Color: "#FF0000", Description: "legacy"}}
+
+	ctx := state.WithDryRun(state.WithProvider(t.Context(), "gitlab"), false)
+
+	require.NoError(t, syncLabels(ctx, client, []scm.EvaluationResult{
+		{Name: "component/legacy", Stale: true},
+		{Name: "component/gone", Stale: true},
+	}))
+
+	require.Empty(t, client.labels.created)
+	require.Empty(t, client.labels.updated)
+}
+
 func TestSyncLabels_dryRunWritesNothing(t *testing.T) {
 	t.Parallel()
 
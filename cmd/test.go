@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/ruletest"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/urfave/cli/v3"
+)
+
+// defaultFixturePath is where 'scm-engine test' looks for fixtures when none are provided
+const defaultFixturePath = ".scm-engine/tests"
+
+var Test = &cli.Command{
+	Name:      "test",
+	Usage:     "Test the configuration file against fixtures with recorded evaluation contexts and expected labels and actions",
+	ArgsUsage: " [fixture file or directory, ...]",
+	Action:    RunTests,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    FlagGlobalConfigFile,
+			Usage:   "Path to a global configuration file. The configuration file will be merged on top of the global configuration",
+			Sources: cli.EnvVars("SCM_ENGINE_GLOBAL_CONFIG_FILE"),
+		},
+	},
+}
+
+func RunTests(ctx context.Context, cCtx *cli.Command) error {
+	ctx = state.WithConfigFilePath(ctx, cCtx.String(FlagConfigFile))
+
+	cfg, err := config.LoadFile(state.ConfigFilePath(ctx))
+	if err != nil {
+		return err
+	}
+
+	if path := cCtx.String(FlagGlobalConfigFile); len(path) > 0 {
+		globalCfg, err := config.LoadFile(path)
+		if err != nil {
+			return err
+		}
+
+		cfg = globalCfg.Merge(cfg)
+	}
+
+	// Includes can't be loaded offline, but the local configuration may still disable rules
+	cfg.ApplyDisable(ctx)
+
+	paths := cCtx.Args().Slice()
+	if len(paths) == 0 {
+		paths = []string{defaultFixturePath}
+	}
+
+	fixtures, err := ruletest.LoadFixtures(paths...)
+	if err != nil {
+		return err
+	}
+
+	results := make([]ruletest.Result, 0, len(fixtures))
+
+	for _, fixture := range fixtures {
+		results = append(results, ruletest.Run(ctx, cfg, fixture))
+	}
+
+	return reportTests(cCtx.Root().Writer, results)
+}
+
+// reportTests writes the outcome of every fixture, and returns an error if any of them failed
+func reportTests(w io.Writer, results []ruletest.Result) error {
+	failed := 0
+
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Fprintf(w, "PASS  %s\n", result.Fixture.Name)
+
+			continue
+		}
+
+		failed++
+
+		fmt.Fprintf(w, "FAIL  %s\n", result.Fixture.Name)
+
+		if result.Err != nil {
+			fmt.Fprintf(w, "      error: %s\n", result.Err)
+
+			continue
+		}
+
+		fmt.Fprintln(w, "      outcome mismatch (-want +got):")
+
+		for line := range strings.SplitSeq(strings.TrimRight(result.Diff, "\n"), "\n") {
+			fmt.Fprintln(w, "    "+line)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d fixtures, %d failed\n", len(results), failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d fixtures failed", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+//nolint:testpackage // reportTests is unexported, and it decides the output and exit code of 'scm-engine test'
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/ruletest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportTests(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	require.NoError(t, reportTests(&out, []ruletest.Result{{Fixture: &ruletest.Fixture{Name: "passes"}}}))
+	require.Equal(t, "PASS  passes\n\n1 fixtures, 0 failed\n", out.String())
+
+	out.Reset()
+
+	err := reportTests(&out, []ruletest.Result{
+		{Fixture: &ruletest.Fixture{Name: "passes"}},
+		{Fixture: &ruletest.Fixture{Name: "mismatch"}, Diff: "-want\n+got\n"},
+		{Fixture: &ruletest.Fixture{Name: "broken"}, Err: errors.New("boom")},
+	})
+
+	// A failing fixture must fail the command, so CI can gate configuration changes
+	require.EqualError(t, err, "2 of 3 fixtures failed")
+	require.Equal(t, `PASS  passes
+FAIL  mismatch
+      outcome mismatch (-want +got):
+    -want
+    +got
+FAIL  broken
+      error: boom
+
+3 fixtures, 2 failed
+`, out.String())
+}
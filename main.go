@@ -69,6 +69,8 @@ func main() {
 		Commands: []*cli.Command{
 			cmd.GitLab,
 			cmd.GitHub,
+			cmd.History,
+			cmd.Test,
 
 			// DEPRECATED COMMANDS
 			{
@@ -2,14 +2,17 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/expr-lang/expr"
-	"github.com/expr-lang/expr/patcher"
 	"github.com/expr-lang/expr/vm"
 	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
 	"github.com/jippi/scm-engine/pkg/stdlib"
 	slogctx "github.com/veqryn/slog-context"
+	"github.com/xhit/go-str2duration/v2"
 )
 
 type (
@@ -32,13 +35,44 @@ type (
 		// See: https://jippi.github.io/scm-engine/configuration/#actions.if
 		If string `json:"if" yaml:"if"`
 
+		// (Optional) How long action.if must have returned true, without interruption, before the action is executed (e.g. '72h' or '3d').
+		//
+		// See: https://jippi.github.io/scm-engine/configuration/#actions.for
+		For string `json:"for,omitempty" yaml:"for,omitempty"`
+
 		// The list of operations to take if the action.if returned true.
 		//
 		// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then
 		Then []ActionStep `json:"then" yaml:"then"`
+
+		// (Optional) The list of operations to take if the action.if returned false.
+		//
+		// See: https://jippi.github.io/scm-engine/configuration/#actions.if.else
+		Else []ActionStep `json:"else,omitempty" yaml:"else,omitempty"`
+
+		// negated is set by Actions.Evaluate when the 'else' steps should be applied
+		negated bool
 	}
 )
 
+// Branch returns the name of the step list that was selected during evaluation, either "then" or "else"
+func (p Action) Branch() string {
+	if p.negated {
+		return "else"
+	}
+
+	return "then"
+}
+
+// Steps returns the steps selected during evaluation, the 'else' steps if action.if returned false, otherwise 'then'
+func (p Action) Steps() []ActionStep {
+	if p.negated {
+		return p.Else
+	}
+
+	return p.Then
+}
+
 func (actions Actions) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]Action, error) {
 	results := []Action{}
 
@@ -53,8 +87,23 @@ func (actions Actions) Evaluate(ctx context.Context, evalContext scm.EvalContext
 			return nil, err
 		}
 
+		if len(action.For) > 0 {
+			if ok, err = action.conditionHeld(ctx, ok); err != nil {
+				return nil, err
+			}
+		}
+
 		if !ok {
-			slogctx.Debug(ctx, "Action evaluated negatively, skipping")
+			if len(action.Else) == 0 {
+				slogctx.Debug(ctx, "Action evaluated negatively, skipping")
+
+				continue
+			}
+
+			slogctx.Debug(ctx, "Action evaluated negatively, using the 'else' steps")
+
+			action.negated = true
+			results = append(results, action)
 
 			continue
 		}
@@ -68,7 +117,7 @@ func (actions Actions) Evaluate(ctx context.Context, evalContext scm.EvalContext
 }
 
 func (p *Action) Evaluate(ctx context.Context, evalContext scm.EvalContext) (bool, error) {
-	program, err := p.Setup(evalContext)
+	program, err := p.Setup(ctx, evalContext)
 	if err != nil {
 		return false, err
 	}
@@ -77,11 +126,78 @@ func (p *Action) Evaluate(ctx context.Context, evalContext scm.EvalContext) (boo
 	return runAndCheckBool(ctx, program, evalContext)
 }
 
-func (p *Action) Setup(evalContext scm.EvalContext) (*vm.Program, error) {
-	opts := make([]expr.Option, 0, len(stdlib.Functions)+4)
-	opts = append(opts, expr.AsBool(), expr.Env(evalContext), stdlib.FunctionRenamer)
-	opts = append(opts, stdlib.Functions...)
-	opts = append(opts, expr.Patch(patcher.WithContext{Name: "ctx"}))
+// Duration returns the parsed 'for' duration, or 0 if not set
+func (p Action) Duration() (time.Duration, error) {
+	if len(p.For) == 0 {
+		return 0, nil
+	}
+
+	duration, err := str2duration.ParseDuration(p.For)
+	if err != nil {
+		return 0, fmt.Errorf("invalid 'for' duration %q: %w", p.For, err)
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("'for' duration %q must be positive", p.For)
+	}
+
+	return duration, nil
+}
+
+// conditionSinceKey is the stored value (see stored_value()) holding when action.if started returning true
+func (p Action) conditionSinceKey() string {
+	return "action_condition_since:" + p.Name
+}
+
+// conditionHeld tracks when action.if started returning true in the state of the Merge Request, and
+// returns true once it has returned true for the 'for' duration. The timer is reset when it returns false
+func (p Action) conditionHeld(ctx context.Context, matched bool) (bool, error) {
+	duration, err := p.Duration()
+	if err != nil {
+		return false, err
+	}
+
+	since, found, err := stdlib.GetStoredValue(ctx, p.conditionSinceKey())
+	if err != nil {
+		return false, err
+	}
+
+	if !matched {
+		if found && !state.IsDryRun(ctx) {
+			slogctx.Debug(ctx, "Action condition is no longer true, resetting the 'for' timer")
+
+			return false, state.StoreFromContext(ctx).Delete(ctx, state.MergeRequestBucket(ctx), p.conditionSinceKey())
+		}
+
+		return false, nil
+	}
+
+	started, ok := since.(time.Time)
+	if !found || !ok {
+		started = time.Now()
+
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Starting the 'for' timer", slog.Time("since", started))
+		} else if err := stdlib.SetStoredValue(ctx, p.conditionSinceKey(), started); err != nil {
+			return false, err
+		}
+	}
+
+	held := time.Since(started)
+	if held < duration {
+		slogctx.Debug(ctx, "Action condition has not been true for long enough", slog.Duration("held", held), slog.Duration("for", duration))
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *Action) Setup(ctx context.Context, evalContext scm.EvalContext) (*vm.Program, error) {
+	options, err := compileOptions(ctx, evalContext, expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
 
-	return expr.Compile(p.If, opts...)
+	return expr.Compile(p.If, options...)
 }
@@ -0,0 +1,348 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// templateFields are the step fields rendered as templates, by action
+var templateFields = map[string][]string{
+	"comment":      {"message"},
+	"discussion":   {"message", "file"},
+	"update_title": {"title"},
+}
+
+// templateOptIn are the actions whose fields are only rendered as templates with 'template: true',
+// since the fields predate templates and existing messages may contain a literal '{{'
+var templateOptIn = map[string]bool{
+	"comment": true,
+}
+
+// isTemplate returns whether the fields of the step are rendered as templates
+func isTemplate(step scm.ActionStep) (bool, error) {
+	action, err := step.OptionalString("action", "")
+	if err != nil {
+		return false, err
+	}
+
+	if !templateOptIn[action] {
+		return true, nil
+	}
+
+	return step.OptionalBool("template", false)
+}
+
+// Supported values for the 'mode' key of the 'comment' step
+const (
+	CommentModeOnce     = "once"
+	CommentModeUpdate   = "update"
+	CommentModeRecreate = "recreate"
+)
+
+// CommentMarker is the hidden marker added to comments with a 'key', so later evaluations can find them
+func CommentMarker(key string) string {
+	return fmt.Sprintf("<!-- scm-engine:comment:%s -->", key)
+}
+
+// Comment applies the 'comment' step.
+//
+// Without a 'key' a new comment is always created. With a 'key' the comment is sticky, and the 'mode'
+// decides what happens when a comment with the same key already exists: 'once' leaves it alone,
+// 'update' (default) edits it and 'recreate' deletes it and creates a new comment
+func Comment(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep, commenter scm.Commenter) error {
+	message, err := CommentMessage(ctx, evalContext, step)
+	if err != nil {
+		return err
+	}
+
+	key, err := step.OptionalString("key", "")
+	if err != nil {
+		return err
+	}
+
+	mode, err := step.OptionalStringEnum("mode", "", CommentModeOnce, CommentModeUpdate, CommentModeRecreate)
+	if err != nil {
+		return err
+	}
+
+	if len(key) == 0 && len(mode) > 0 {
+		return errors.New("step field 'mode' requires the 'key' field")
+	}
+
+	if len(mode) == 0 {
+		mode = CommentModeUpdate
+	}
+
+	if len(key) == 0 {
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Commenting on MR", slog.String("message", message))
+
+			return nil
+		}
+
+		return commenter.CreateComment(ctx, message)
+	}
+
+	marker := CommentMarker(key)
+	body := message + "\n\n" + marker
+
+	comments, err := commenter.ListComments(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list comments: %w", err)
+	}
+
+	// Only the bot's own comments are sticky, people may quote or paste the marker
+	botUsername, err := commenter.BotUsername(ctx)
+	if err != nil {
+		return err
+	}
+
+	var existing *scm.Comment
+
+	for _, comment := range comments {
+		if scm.IsSameUser(comment.Author, botUsername) && strings.Contains(comment.Body, marker) {
+			existing = &comment
+
+			break
+		}
+	}
+
+	ctx = slogctx.With(ctx, slog.String("comment_key", key), slog.String("comment_mode", mode))
+
+	switch {
+	case existing == nil:
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Commenting on MR", slog.String("message", message))
+
+			return nil
+		}
+
+		return commenter.CreateComment(ctx, body)
+
+	case mode == CommentModeOnce:
+		slogctx.Debug(ctx, "Comment already exists, skipping")
+
+		return nil
+
+	case mode == CommentModeUpdate:
+		if existing.Body == body {
+			slogctx.Debug(ctx, "Comment is already up to date, skipping")
+
+			return nil
+		}
+
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Updating comment on MR", slog.Int64("comment_id", existing.ID), slog.String("message", message))
+
+			return nil
+		}
+
+		return commenter.UpdateComment(ctx, existing.ID, body)
+
+	default: // CommentModeRecreate
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Recreating comment on MR", slog.Int64("comment_id", existing.ID), slog.String("message", message))
+
+			return nil
+		}
+
+		if err := commenter.DeleteComment(ctx, existing.ID); err != nil {
+			return fmt.Errorf("could not delete comment: %w", err)
+		}
+
+		return commenter.CreateComment(ctx, body)
+	}
+}
+
+// CommentMessage returns the 'message' of a 'comment' or 'discussion' step, rendered as a template when enabled
+func CommentMessage(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) (string, error) {
+	message, err := step.RequiredString("message")
+	if err != nil {
+		return "", err
+	}
+
+	if len(message) == 0 {
+		return "", errors.New("step field 'message' must not be an empty string")
+	}
+
+	enabled, err := isTemplate(step)
+	if err != nil {
+		return "", err
+	}
+
+	if !enabled {
+		return message, nil
+	}
+
+	message, err = RenderTemplate(ctx, evalContext, message)
+	if err != nil {
+		return "", fmt.Errorf("could not render step field 'message': %w", err)
+	}
+
+	return message, nil
+}
+
+// RenderTemplate replaces each '{{ script }}' in the template with the output of the expr-lang script,
+// for example '@{{ merge_request.author.username }}'.
+//
+// Strings are inserted as-is, other values are formatted with fmt. A literal '{{' is written as '{{"{{"}}'
+func RenderTemplate(ctx context.Context, evalContext scm.EvalContext, template string) (string, error) {
+	parts, err := compileTemplate(ctx, evalContext, template)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+
+	for _, part := range parts {
+		if part.program == nil {
+			output.WriteString(part.text)
+
+			continue
+		}
+
+		value, err := expr.Run(part.program, evalContext)
+		if err != nil {
+			return "", fmt.Errorf("could not evaluate '{{ %s }}': %w", part.text, err)
+		}
+
+		switch value := value.(type) {
+		case string:
+			output.WriteString(value)
+
+		case nil:
+			// Render nothing, like an empty string
+
+		default:
+			fmt.Fprint(&output, value)
+		}
+	}
+
+	return output.String(), nil
+}
+
+// templatePart is either literal text, or a compiled script (with its source as text)
+type templatePart struct {
+	text    string
+	program *vm.Program
+}
+
+// compileTemplate splits the template into literal text and compiled '{{ script }}' parts
+func compileTemplate(ctx context.Context, evalContext scm.EvalContext, template string) ([]templatePart, error) {
+	// Plain text, nothing to compile
+	if !strings.Contains(template, "{{") {
+		return []templatePart{{text: template}}, nil
+	}
+
+	options, err := compileOptions(ctx, evalContext, expr.AsAny())
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []templatePart
+
+	for len(template) > 0 {
+		start := strings.Index(template, "{{")
+		if start == -1 {
+			parts = append(parts, templatePart{text: template})
+
+			break
+		}
+
+		end := scriptEnd(template[start+2:])
+		if end == -1 {
+			return nil, fmt.Errorf("unterminated '{{' at %q", template[start:])
+		}
+
+		script := strings.TrimSpace(template[start+2 : start+2+end])
+		if len(script) == 0 {
+			return nil, errors.New("empty '{{ }}' in template")
+		}
+
+		program, err := expr.Compile(script, options...)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile '{{ %s }}' into valid expr-lang syntax: %w", script, err)
+		}
+
+		parts = append(parts,
+			templatePart{text: template[:start]},
+			templatePart{text: script, program: program},
+		)
+
+		template = template[start+2+end+2:]
+	}
+
+	return parts, nil
+}
+
+// scriptEnd returns the index of the '}}' closing the script, or -1 if there is none.
+//
+// Braces of map literals and braces within string literals are skipped, so '{{ {"a": {"b": 1}}.a.b }}'
+// and '{{"{{"}}' are read as a whole
+func scriptEnd(script string) int {
+	depth := 0
+
+	for idx := 0; idx < len(script); idx++ {
+		switch char := script[idx]; char {
+		case '"', '\'', '`':
+			// Skip to the end of the string literal, backquoted strings have no escapes
+			for idx++; idx < len(script) && script[idx] != char; idx++ {
+				if script[idx] == '\\' && char != '`' {
+					idx++
+				}
+			}
+
+		case '{':
+			depth++
+
+		case '}':
+			if depth > 0 {
+				depth--
+
+				continue
+			}
+
+			if idx+1 < len(script) && script[idx+1] == '}' {
+				return idx
+			}
+		}
+	}
+
+	return -1
+}
+
+// lintTemplates compiles the templates of the step, so syntax and type errors are reported before the step is applied
+func (step ActionStep) lintTemplates(ctx context.Context, evalContext scm.EvalContext) error {
+	action, _ := step["action"].(string)
+
+	enabled, err := isTemplate(step)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	for _, field := range templateFields[action] {
+		template, ok := step[field].(string)
+		if !ok {
+			continue
+		}
+
+		if _, err := compileTemplate(ctx, evalContext, template); err != nil {
+			return fmt.Errorf("step field '%s' is not a valid template: %w", field, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Parallel()
+
+	evalContext := evalContext("bug", "needs-review")
+	evalContext.MergeRequest.Author = &gitlab.ContextUser{Username: "jippi"}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "plain text is unchanged",
+			template: "Hello world",
+			want:     "Hello world",
+		},
+		{
+			name:     "context fields",
+			template: "Hey @{{ merge_request.author.username }}, please take a look",
+			want:     "Hey @jippi, please take a look",
+		},
+		{
+			name:     "stdlib functions and non-string values",
+			template: "{{ len(merge_request.labels) }} labels: {{ join(map(merge_request.labels, .title), ', ') }}",
+			want:     "2 labels: bug, needs-review",
+		},
+		{
+			name:     "nested map literal",
+			template: `{{ {"a": {"b": 1}}.a.b }} and {{ {"c": "}}"}.c }}`,
+			want:     "1 and }}",
+		},
+		{
+			name:     "escaped braces",
+			template: `Use ${{"{{"}} secrets.TOKEN }}`,
+			want:     "Use ${{ secrets.TOKEN }}",
+		},
+		{
+			name:     "unterminated script",
+			template: "Hey @{{ merge_request.author.username",
+			wantErr:  `unterminated '{{' at "{{ merge_request.author.username"`,
+		},
+		{
+			name:     "unknown field",
+			template: "Hey @{{ merge_request.nope }}",
+			wantErr:  "could not compile '{{ merge_request.nope }}' into valid expr-lang syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := config.RenderTemplate(t.Context(), evalContext, tt.template)
+			if len(tt.wantErr) > 0 {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommentMessage(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Definitions: config.Definitions{"label_count": "len(merge_request.labels)"}}
+
+	ctx := config.WithConfig(t.Context(), cfg)
+
+	message, err := config.CommentMessage(ctx, evalContext("bug"), config.ActionStep{"action": "comment", "template": true, "message": "{{ label_count }} label(s)"})
+	require.NoError(t, err)
+	require.Equal(t, "1 label(s)", message)
+
+	// Comments predate templates, so the message is sent as-is unless 'template' is enabled
+	message, err = config.CommentMessage(ctx, evalContext("bug"), config.ActionStep{"action": "comment", "message": "Use ${{ secrets.TOKEN }}"})
+	require.NoError(t, err)
+	require.Equal(t, "Use ${{ secrets.TOKEN }}", message)
+
+	// Other actions always render their fields as templates
+	message, err = config.CommentMessage(ctx, evalContext("bug"), config.ActionStep{"action": "discussion", "message": "{{ label_count }} label(s)"})
+	require.NoError(t, err)
+	require.Equal(t, "1 label(s)", message)
+
+	_, err = config.CommentMessage(ctx, evalContext(), config.ActionStep{"action": "comment", "message": ""})
+	require.EqualError(t, err, "step field 'message' must not be an empty string")
+}
+
+func TestConfig_Lint_templates(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Actions: config.Actions{{
+			Name: "welcome",
+			If:   "true",
+			Then: []config.ActionStep{{"action": "comment", "template": true, "message": "Hey @{{ merge_request.author.usernme }}"}},
+		}},
+	}
+
+	err := cfg.Lint(t.Context(), evalContext())
+	require.ErrorContains(t, err, `Action "welcome" failed validation: step field 'message' is not a valid template`)
+
+	// A literal '{{' is fine when the comment isn't a template
+	cfg.Actions[0].Then[0] = config.ActionStep{"action": "comment", "message": "Use ${{ secrets.TOKEN }} in your workflow"}
+
+	require.NoError(t, cfg.Lint(t.Context(), evalContext()))
+}
+
+// fakeCommenter keeps the comments in memory, and records the calls made
+type fakeCommenter struct {
+	comments []scm.Comment
+	calls    []string
+}
+
+// fakeBotUsername is the author of the comments created by the fakes
+const fakeBotUsername = "scm-engine"
+
+func (c *fakeCommenter) BotUsername(context.Context) (string, error) {
+	return fakeBotUsername, nil
+}
+
+func (c *fakeCommenter) CreateComment(_ context.Context, body string) error {
+	c.calls = append(c.calls, "create")
+	c.comments = append(c.comments, scm.Comment{ID: int64(len(c.comments) + 1), Body: body, Author: fakeBotUsername})
+
+	return nil
+}
+
+func (c *fakeCommenter) DeleteComment(_ context.Context, id int64) error {
+	c.calls = append(c.calls, fmt.Sprintf("delete %d", id))
+	c.comments = slices.DeleteFunc(c.comments, func(comment scm.Comment) bool { return comment.ID == id })
+
+	return nil
+}
+
+func (c *fakeCommenter) ListComments(context.Context) ([]scm.Comment, error) {
+	return slices.Clone(c.comments), nil
+}
+
+func (c *fakeCommenter) UpdateComment(_ context.Context, id int64, body string) error {
+	c.calls = append(c.calls, fmt.Sprintf("update %d", id))
+
+	for idx := range c.comments {
+		if c.comments[idx].ID == id {
+			c.comments[idx].Body = body
+		}
+	}
+
+	return nil
+}
+
+func TestComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mode      string
+		wantCalls []string
+		wantBody  string
+	}{
+		{
+			name:      "update is the default",
+			wantCalls: []string{"create", "update 2"},
+			wantBody:  "1 labels",
+		},
+		{
+			name:      "once",
+			mode:      config.CommentModeOnce,
+			wantCalls: []string{"create"},
+			wantBody:  "0 labels",
+		},
+		{
+			name:      "recreate",
+			mode:      config.CommentModeRecreate,
+			wantCalls: []string{"create", "delete 2", "create", "delete 2", "create"},
+			wantBody:  "1 labels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := state.WithDryRun(t.Context(), false)
+
+			// An unrelated comment, which must never be touched
+			commenter := &fakeCommenter{comments: []scm.Comment{{ID: 1, Body: "LGTM", Author: "jippi"}}}
+
+			step := config.ActionStep{"action": "comment", "template": true, "message": "{{ len(merge_request.labels) }} labels", "key": "labels"}
+			if len(tt.mode) > 0 {
+				step["mode"] = tt.mode
+			}
+
+			require.NoError(t, config.Comment(ctx, evalContext(), step, commenter))
+			require.NoError(t, config.Comment(ctx, evalContext("bug"), step, commenter))
+
+			// Running again without changes is a no-op, unless the comment is recreated every time
+			require.NoError(t, config.Comment(ctx, evalContext("bug"), step, commenter))
+
+			require.Equal(t, tt.wantCalls, commenter.calls)
+			require.Len(t, commenter.comments, 2)
+			require.Equal(t, "LGTM", commenter.comments[0].Body)
+
+			require.Equal(t, tt.wantBody+"\n\n"+config.CommentMarker("labels"), commenter.comments[1].Body)
+		})
+	}
+}
+
+func TestComment_withoutKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), false)
+	commenter := &fakeCommenter{}
+
+	step := config.ActionStep{"action": "comment", "message": "Hello"}
+
+	require.NoError(t, config.Comment(ctx, evalContext(), step, commenter))
+	require.NoError(t, config.Comment(ctx, evalContext(), step, commenter))
+	require.Equal(t, []string{"create", "create"}, commenter.calls)
+	require.Equal(t, "Hello", commenter.comments[0].Body)
+
+	step["mode"] = config.CommentModeOnce
+
+	require.EqualError(t, config.Comment(ctx, evalContext(), step, commenter), "step field 'mode' requires the 'key' field")
+}
+
+func TestComment_dryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), true)
+	commenter := &fakeCommenter{comments: []scm.Comment{{ID: 1, Body: "old\n\n" + config.CommentMarker("labels"), Author: fakeBotUsername}}}
+
+	require.NoError(t, config.Comment(ctx, evalContext(), config.ActionStep{"action": "comment", "message": "new", "key": "labels"}, commenter))
+	require.Empty(t, commenter.calls)
+}
+
+// Comments by other users quoting the marker are never treated as the sticky comment
+func TestComment_otherAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), false)
+	commenter := &fakeCommenter{comments: []scm.Comment{{ID: 1, Body: "> old\n> " + config.CommentMarker("labels"), Author: "jippi"}}}
+
+	require.NoError(t, config.Comment(ctx, evalContext(), config.ActionStep{"action": "comment", "message": "new", "key": "labels"}, commenter))
+	require.Equal(t, []string{"create"}, commenter.calls)
+	require.Len(t, commenter.comments, 2)
+}
+
+// GitHub Apps are listed with a "[bot]" suffix by the REST API, but not by the GraphQL viewer
+func TestComment_botAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), false)
+	commenter := &fakeCommenter{comments: []scm.Comment{{ID: 1, Body: "old\n\n" + config.CommentMarker("labels"), Author: fakeBotUsername + "[bot]"}}}
+
+	require.NoError(t, config.Comment(ctx, evalContext(), config.ActionStep{"action": "comment", "message": "new", "key": "labels"}, commenter))
+	require.Equal(t, []string{"update 1"}, commenter.calls)
+}
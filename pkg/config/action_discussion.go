@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// discussionMarkerPrefix is shared by the markers of all discussions started by scm-engine
+const discussionMarkerPrefix = "<!-- scm-engine:discussion:"
+
+// DiscussionMarker is the hidden marker added to discussions, so later evaluations can find and resolve them
+func DiscussionMarker(key string) string {
+	return discussionMarkerPrefix + key + " -->"
+}
+
+// Discussion applies the 'discussion' step, starting a resolvable thread unless an unresolved thread
+// with the same 'key' already exists.
+//
+// The thread is anchored to 'file' (and 'line') when set, the file must be changed in the Merge Request
+func Discussion(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep, discussioner scm.Discussioner) error {
+	message, err := CommentMessage(ctx, evalContext, step)
+	if err != nil {
+		return err
+	}
+
+	key, err := step.RequiredString("key")
+	if err != nil {
+		return err
+	}
+
+	position, err := discussionPosition(ctx, evalContext, step)
+	if err != nil {
+		return err
+	}
+
+	marker := DiscussionMarker(key)
+
+	discussions, err := discussioner.ListDiscussions(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list discussions: %w", err)
+	}
+
+	botUsername, err := discussioner.BotUsername(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx = slogctx.With(ctx, slog.String("discussion_key", key))
+
+	for _, discussion := range discussions {
+		if !discussion.Resolved && isBotDiscussion(discussion, botUsername, marker) {
+			slogctx.Debug(ctx, "Unresolved discussion already exists, skipping", slog.String("discussion_id", discussion.ID))
+
+			return nil
+		}
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Starting discussion on MR", slog.String("message", message), slog.Any("position", position))
+
+		return nil
+	}
+
+	return discussioner.CreateDiscussion(ctx, message+"\n\n"+marker, position)
+}
+
+// ResolveDiscussions applies the 'resolve_discussions' step, resolving the unresolved threads started by
+// the 'discussion' step. With a 'key' only the threads with that key are resolved
+func ResolveDiscussions(ctx context.Context, step scm.ActionStep, discussioner scm.Discussioner) error {
+	key, err := step.OptionalString("key", "")
+	if err != nil {
+		return err
+	}
+
+	marker := discussionMarkerPrefix
+	if len(key) > 0 {
+		marker = DiscussionMarker(key)
+	}
+
+	discussions, err := discussioner.ListDiscussions(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list discussions: %w", err)
+	}
+
+	botUsername, err := discussioner.BotUsername(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, discussion := range discussions {
+		if discussion.Resolved || !isBotDiscussion(discussion, botUsername, marker) {
+			continue
+		}
+
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Resolving discussion on MR", slog.String("discussion_id", discussion.ID))
+
+			continue
+		}
+
+		if err := discussioner.ResolveDiscussion(ctx, discussion.ID); err != nil {
+			return fmt.Errorf("could not resolve discussion %s: %w", discussion.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// lintDiscussion reports 'discussion' steps without the 'file' field on GitHub, where review threads must be anchored to a file
+func (step ActionStep) lintDiscussion(ctx context.Context) error {
+	if action, _ := step["action"].(string); action != "discussion" || state.Provider(ctx) != "github" {
+		return nil
+	}
+
+	if _, ok := step["file"]; !ok {
+		return errors.New("step field 'file' is required by the 'discussion' action on GitHub, review threads must be anchored to a file")
+	}
+
+	return nil
+}
+
+// isBotDiscussion returns whether the thread was started by the bot with the marker, people may quote or paste the marker
+func isBotDiscussion(discussion scm.Discussion, botUsername, marker string) bool {
+	return scm.IsSameUser(discussion.Author, botUsername) && strings.Contains(discussion.Body, marker)
+}
+
+// discussionPosition reads the optional 'file' (rendered as a template) and 'line' step fields
+func discussionPosition(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) (*scm.DiscussionPosition, error) {
+	file, err := step.OptionalString("file", "")
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := step.OptionalInt("line", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file) == 0 {
+		if line != 0 {
+			return nil, errors.New("step field 'line' requires the 'file' field")
+		}
+
+		return nil, nil //nolint:nilnil // a discussion without a position is not anchored to a file
+	}
+
+	file, err = RenderTemplate(ctx, evalContext, file)
+	if err != nil {
+		return nil, fmt.Errorf("could not render step field 'file': %w", err)
+	}
+
+	if !slices.Contains(evalContext.GetModifiedFiles(), file) {
+		return nil, fmt.Errorf("step field 'file' must be a file changed in the Merge Request, got %q", file)
+	}
+
+	if line < 0 {
+		return nil, fmt.Errorf("step field 'line' must be a positive number, got %d", line)
+	}
+
+	return &scm.DiscussionPosition{Path: file, Line: line}, nil
+}
@@ -0,0 +1,217 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiscussioner keeps the discussions in memory, and records the calls made
+type fakeDiscussioner struct {
+	discussions []scm.Discussion
+	positions   []*scm.DiscussionPosition
+	calls       []string
+}
+
+func (d *fakeDiscussioner) BotUsername(context.Context) (string, error) {
+	return fakeBotUsername, nil
+}
+
+func (d *fakeDiscussioner) CreateDiscussion(_ context.Context, body string, position *scm.DiscussionPosition) error {
+	d.calls = append(d.calls, "create")
+	d.discussions = append(d.discussions, scm.Discussion{ID: fmt.Sprint(len(d.discussions) + 1), Body: body, Author: fakeBotUsername})
+	d.positions = append(d.positions, position)
+
+	return nil
+}
+
+func (d *fakeDiscussioner) ListDiscussions(context.Context) ([]scm.Discussion, error) {
+	return slices.Clone(d.discussions), nil
+}
+
+func (d *fakeDiscussioner) ResolveDiscussion(_ context.Context, id string) error {
+	d.calls = append(d.calls, "resolve "+id)
+
+	for idx := range d.discussions {
+		if d.discussions[idx].ID == id {
+			d.discussions[idx].Resolved = true
+		}
+	}
+
+	return nil
+}
+
+func discussionContext() *gitlab.Context {
+	evalContext := evalContext()
+	evalContext.MergeRequest.DiffStats = []gitlab.ContextDiffStat{
+		{Path: "go.mod", Additions: 1},
+		{Path: "main.go", Additions: 1200},
+	}
+
+	return evalContext
+}
+
+func TestDiscussion(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), false)
+	discussioner := &fakeDiscussioner{discussions: []scm.Discussion{
+		{ID: "1", Body: "Looks good", Author: "jippi"},
+		{ID: "2", Body: "> Quoting\n> " + config.DiscussionMarker("too-big"), Author: "jippi"},
+	}}
+
+	step := config.ActionStep{
+		"action":  "discussion",
+		"key":     "too-big",
+		"message": "This adds {{ merge_request.diff_stats | map(.additions) | sum() }} lines",
+		"file":    "{{ merge_request.diff_stats[1].path }}",
+		"line":    3,
+	}
+
+	// Only one thread is started while it's unresolved
+	require.NoError(t, config.Discussion(ctx, discussionContext(), step, discussioner))
+	require.NoError(t, config.Discussion(ctx, discussionContext(), step, discussioner))
+	require.Equal(t, []string{"create"}, discussioner.calls)
+	require.Equal(t, "This adds 1201 lines\n\n"+config.DiscussionMarker("too-big"), discussioner.discussions[2].Body)
+	require.Equal(t, &scm.DiscussionPosition{Path: "main.go", Line: 3}, discussioner.positions[0])
+
+	// Threads started by people are left alone, even when they quote the marker
+	require.NoError(t, config.ResolveDiscussions(ctx, config.ActionStep{"action": "resolve_discussions"}, discussioner))
+	require.Equal(t, []string{"create", "resolve 3"}, discussioner.calls)
+	require.False(t, discussioner.discussions[0].Resolved)
+	require.False(t, discussioner.discussions[1].Resolved)
+
+	// A new thread is started once the old one is resolved
+	require.NoError(t, config.Discussion(ctx, discussionContext(), step, discussioner))
+	require.Equal(t, []string{"create", "resolve 3", "create"}, discussioner.calls)
+}
+
+func TestDiscussion_position(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    any
+		line    any
+		want    *scm.DiscussionPosition
+		wantErr string
+	}{
+		{
+			name: "not anchored",
+			want: nil,
+		},
+		{
+			name: "anchored to a file",
+			file: "go.mod",
+			want: &scm.DiscussionPosition{Path: "go.mod"},
+		},
+		{
+			name:    "the file must be changed",
+			file:    "README.md",
+			wantErr: `step field 'file' must be a file changed in the Merge Request, got "README.md"`,
+		},
+		{
+			name:    "line requires a file",
+			line:    3,
+			wantErr: "step field 'line' requires the 'file' field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := state.WithDryRun(t.Context(), false)
+			discussioner := &fakeDiscussioner{}
+
+			step := config.ActionStep{"action": "discussion", "key": "position", "message": "Hello"}
+			if tt.file != nil {
+				step["file"] = tt.file
+			}
+
+			if tt.line != nil {
+				step["line"] = tt.line
+			}
+
+			err := config.Discussion(ctx, discussionContext(), step, discussioner)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				require.Empty(t, discussioner.calls)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []*scm.DiscussionPosition{tt.want}, discussioner.positions)
+		})
+	}
+}
+
+func TestResolveDiscussions_key(t *testing.T) {
+	t.Parallel()
+
+	discussioner := &fakeDiscussioner{discussions: []scm.Discussion{
+		{ID: "1", Body: "big\n\n" + config.DiscussionMarker("too-big"), Author: fakeBotUsername},
+		{ID: "2", Body: "lint\n\n" + config.DiscussionMarker("lint"), Author: fakeBotUsername},
+		{ID: "3", Body: "old\n\n" + config.DiscussionMarker("lint"), Author: fakeBotUsername, Resolved: true},
+	}}
+
+	// Nothing is resolved in dry run
+	ctx := state.WithDryRun(t.Context(), true)
+
+	require.NoError(t, config.ResolveDiscussions(ctx, config.ActionStep{"action": "resolve_discussions", "key": "lint"}, discussioner))
+	require.Empty(t, discussioner.calls)
+
+	ctx = state.WithDryRun(t.Context(), false)
+
+	require.NoError(t, config.ResolveDiscussions(ctx, config.ActionStep{"action": "resolve_discussions", "key": "lint"}, discussioner))
+	require.Equal(t, []string{"resolve 2"}, discussioner.calls)
+}
+
+// GitHub Apps are listed with a "[bot]" suffix by the REST API, but not by the GraphQL API
+func TestDiscussion_botAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithDryRun(t.Context(), false)
+	discussioner := &fakeDiscussioner{discussions: []scm.Discussion{
+		{ID: "1", Body: "big\n\n" + config.DiscussionMarker("too-big"), Author: fakeBotUsername + "[bot]"},
+	}}
+
+	step := config.ActionStep{"action": "discussion", "key": "too-big", "message": "Too big"}
+
+	require.NoError(t, config.Discussion(ctx, discussionContext(), step, discussioner))
+	require.Empty(t, discussioner.calls)
+
+	require.NoError(t, config.ResolveDiscussions(ctx, config.ActionStep{"action": "resolve_discussions"}, discussioner))
+	require.Equal(t, []string{"resolve 1"}, discussioner.calls)
+}
+
+// GitHub review threads must be anchored to a file, which is caught when the configuration is validated
+func TestConfig_Lint_discussionWithoutFile(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Actions: config.Actions{{
+			Name: "too-big",
+			If:   "true",
+			Then: []config.ActionStep{{"action": "discussion", "key": "too-big", "message": "Too big"}},
+		}},
+	}
+
+	err := cfg.Lint(state.WithProvider(t.Context(), "github"), discussionContext())
+	require.ErrorContains(t, err, `Action "too-big" failed validation: step field 'file' is required by the 'discussion' action on GitHub`)
+
+	// GitLab threads can be started on the Merge Request as a whole
+	require.NoError(t, cfg.Lint(state.WithProvider(t.Context(), "gitlab"), discussionContext()))
+
+	cfg.Actions[0].Then[0]["file"] = "main.go"
+
+	require.NoError(t, cfg.Lint(state.WithProvider(t.Context(), "github"), discussionContext()))
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+// Rebase applies the 'rebase' action step, rebasing the source branch on the target branch
+// when the Merge Request is updated
+func Rebase(update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	skipCI, err := step.OptionalBool("skip_ci", false)
+	if err != nil {
+		return err
+	}
+
+	update.Rebase = &scm.RebaseOptions{SkipCI: skipCI}
+
+	return nil
+}
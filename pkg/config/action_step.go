@@ -16,15 +16,27 @@ type actionList struct {
 var actions = []actionList{
 	{name: "add_label", instance: AddLabelAction{}},
 	{name: "approve", instance: ApproveAction{}},
+	{name: "assign", instance: AssignAction{}},
 	{name: "assign_reviewers", instance: AssignReviewers{}},
+	{name: "auto_merge", instance: AutoMergeAction{}},
 	{name: "close", instance: CloseAction{}},
 	{name: "comment", instance: CommentAction{}},
+	{name: "delete_stored_value", instance: DeleteStoredValueAction{}},
+	{name: "discussion", instance: DiscussionAction{}},
 	{name: "lock_discussion", instance: LockDiscussionAction{}},
+	{name: "mark_draft", instance: MarkDraftAction{}},
+	{name: "mark_ready", instance: MarkReadyAction{}},
+	{name: "merge", instance: MergeAction{}},
+	{name: "rebase", instance: RebaseAction{}},
 	{name: "remove_label", instance: RemoveLabelAction{}},
 	{name: "reopen", instance: ReopenAction{}},
+	{name: "resolve_discussions", instance: ResolveDiscussionsAction{}},
+	{name: "set_milestone", instance: SetMilestoneAction{}},
+	{name: "store_value", instance: StoreValueAction{}},
 	{name: "unapprove", instance: UnapproveAction{}},
 	{name: "unlock_discussion", instance: UnlockDiscussionAction{}},
 	{name: "update_description", instance: UpdateDescriptionAction{}},
+	{name: "update_title", instance: UpdateTitleAction{}},
 }
 
 // ActionNames returns the name of every action that may be used in the
@@ -44,8 +56,21 @@ type BaseAction struct {
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
 	Action string `json:"action" yaml:"action"`
+
+	// (Optional) What to do if the step fails. 'fail' (default) stops the evaluation,
+	// 'continue' moves on to the next step and 'skip_action' moves on to the next action.
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.on_error
+	OnError string `json:"on_error,omitempty" yaml:"on_error,omitempty" jsonschema:"enum=continue,enum=fail,enum=skip_action"`
 }
 
+// Supported values for the 'on_error' step key
+const (
+	OnErrorContinue   = "continue"
+	OnErrorFail       = "fail"
+	OnErrorSkipAction = "skip_action"
+)
+
 // Hello World?
 type ApproveAction struct {
 	BaseAction
@@ -67,6 +92,14 @@ type ReopenAction struct {
 	BaseAction
 }
 
+// Rebases the source branch on the target branch (GitLab) or updates it with the base branch (GitHub)
+type RebaseAction struct {
+	BaseAction
+
+	// (Optional) Don't start a pipeline for the rebased commits (GitLab only)
+	SkipCI bool `json:"skip_ci,omitempty" yaml:"skip_ci,omitempty"`
+}
+
 type RemoveLabelAction struct {
 	BaseAction
 
@@ -83,6 +116,63 @@ type CommentAction struct {
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
 	Message string `json:"message" yaml:"message"`
+
+	// (Optional) Render the message as a template, each '{{ script }}' is replaced with the output of the Expr Lang script
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Template bool `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// (Optional) Makes the comment sticky, later evaluations find the comment by this key instead of creating a new one
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// (Optional) What to do when a comment with the same 'key' exists. 'update' (default) edits it,
+	// 'once' leaves it alone and 'recreate' deletes it and creates a new comment
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty" jsonschema:"enum=once,enum=update,enum=recreate"`
+}
+
+// Starts a resolvable thread on the Merge Request, skipped while an unresolved thread with the same key exists
+type DiscussionAction struct {
+	BaseAction
+
+	// The message that starts the thread, each '{{ script }}' is replaced with the output of the Expr Lang script
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Message string `json:"message" yaml:"message"`
+
+	// Identifies the thread, so later evaluations don't start it again and 'resolve_discussions' can resolve it
+	Key string `json:"key" yaml:"key"`
+
+	// (Optional) Anchor the thread to this changed file, each '{{ script }}' is replaced with the output of the Expr Lang script. Required on GitHub
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// (Optional) Anchor the thread to this line in the new version of 'file'
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+}
+
+// Resolves the unresolved threads started by the 'discussion' action
+type ResolveDiscussionsAction struct {
+	BaseAction
+
+	// (Optional) Only resolve the threads with this key
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// Assigns the Merge Request, skipped when it already has assignees
+type AssignAction struct {
+	BaseAction
+
+	// (Optional) Who to assign, 'author' (default), 'codeowners' or 'static'
+	Source string `json:"source,omitempty" yaml:"source,omitempty" jsonschema:"enum=author,enum=codeowners,enum=static"`
+	// The static user IDs set for source=static (GitLab)
+	UserIDs []string `json:"user_ids,omitempty" yaml:"user_ids,omitempty"`
+	// The static usernames set for source=static (GitHub)
+	Usernames []string `json:"usernames,omitempty" yaml:"usernames,omitempty"`
+	// (Optional) The max number of assignees, picked at random (default: 1)
+	Limit int `json:"limit,omitempty" yaml:"limit,omitempty" jsonschema:"minimum=1"`
 }
 
 type AssignReviewers struct {
@@ -90,12 +180,42 @@ type AssignReviewers struct {
 
 	// The source of the reviewers
 	Source *string `json:"source,omitempty" yaml:"source,omitempty" jsonschema:"enum=codeowners,enum=backstage,enum=static"`
-	// The static user IDs set for source=static
+	// The static user IDs set for source=static (GitLab)
 	UserIDs []string `json:"user_ids,omitempty" yaml:"user_ids,omitempty"`
+	// The static usernames set for source=static (GitHub), teams are written as "org/team-slug"
+	Usernames []string `json:"usernames,omitempty" yaml:"usernames,omitempty"`
 	// The max number of reviewers to assign
-	Limit int `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Limit int `json:"limit,omitempty" yaml:"limit,omitempty" jsonschema:"minimum=1"`
 	// The mode of assigning reviewers
-	Mode string `json:"mode,omitempty" yaml:"mode,omitempty" jsonschema:"enum=random"`
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty" jsonschema:"enum=random,enum=least_busy,enum=round_robin"`
+	// Usernames or user IDs that must never be assigned
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// The minimum number of distinct teams the assigned reviewers must belong to
+	MinTeams int `json:"min_teams,omitempty" yaml:"min_teams,omitempty"`
+	// The members (usernames or user IDs) of each team, used by min_teams
+	Teams map[string][]string `json:"teams,omitempty" yaml:"teams,omitempty"`
+}
+
+// Merges the Merge Request right away
+type MergeAction struct {
+	BaseAction
+
+	// (Optional) Squash the commits when merging
+	Squash bool `json:"squash,omitempty" yaml:"squash,omitempty"`
+	// (Optional) Only merge if no commits were pushed after the evaluated commit (default: true)
+	ShaGuard *bool `json:"sha_guard,omitempty" yaml:"sha_guard,omitempty"`
+}
+
+// Merges the Merge Request once the pipeline (GitLab) or required checks (GitHub) succeed
+type AutoMergeAction struct {
+	BaseAction
+
+	// (Optional) 'set' (default) enables auto-merge, 'cancel' disables it
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty" jsonschema:"enum=set,enum=cancel"`
+	// (Optional) Squash the commits when merging
+	Squash bool `json:"squash,omitempty" yaml:"squash,omitempty"`
+	// (Optional) Only merge if no commits were pushed after the evaluated commit (default: true)
+	ShaGuard *bool `json:"sha_guard,omitempty" yaml:"sha_guard,omitempty"`
 }
 
 type AddLabelAction struct {
@@ -107,6 +227,26 @@ type AddLabelAction struct {
 	Label string `json:"label" yaml:"label"`
 }
 
+// Marks the Merge Request as draft
+type MarkDraftAction struct {
+	BaseAction
+}
+
+// Marks the Merge Request as ready
+type MarkReadyAction struct {
+	BaseAction
+}
+
+// Sets the milestone of the Merge Request
+type SetMilestoneAction struct {
+	BaseAction
+
+	// The title of the milestone
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Milestone string `json:"milestone" yaml:"milestone"`
+}
+
 type UnlockDiscussionAction struct {
 	BaseAction
 }
@@ -123,6 +263,36 @@ type UpdateDescriptionAction struct {
 	Replace map[string]string `json:"replace" yaml:"replace"`
 }
 
+// Updates the Merge Request title
+type UpdateTitleAction struct {
+	BaseAction
+
+	// The new title, each '{{ script }}' is replaced with the output of the Expr Lang script
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Title string `json:"title" yaml:"title"`
+}
+
+// Stores a value in the state of the Merge Request, so later evaluations can read it with stored_value()
+type StoreValueAction struct {
+	BaseAction
+
+	// The key to store the value under
+	Key string `json:"key" yaml:"key"`
+	// An Expr Lang expression, the output is stored as the value
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#actions.if.then.action
+	Value string `json:"value" yaml:"value"`
+}
+
+// Deletes a value from the state of the Merge Request
+type DeleteStoredValueAction struct {
+	BaseAction
+
+	// The key to delete
+	Key string `json:"key" yaml:"key"`
+}
+
 // This key controls what kind of action that should be taken.
 //
 // See: https://jippi.github.io/scm-engine/configuration/#actions.if.then
@@ -175,6 +345,11 @@ func (step ActionStep) JSONSchema() *jsonschema.Schema {
 	}
 }
 
+// OnError returns the 'on_error' policy of the step, defaulting to 'fail'
+func (step ActionStep) OnError() (string, error) {
+	return step.OptionalStringEnum("on_error", OnErrorFail, OnErrorContinue, OnErrorFail, OnErrorSkipAction)
+}
+
 func (step ActionStep) RequiredInt(name string) (int, error) {
 	value, ok := step[name]
 	if !ok {
@@ -195,29 +370,12 @@ func (step ActionStep) RequiredStringSlice(name string) ([]string, error) {
 		return nil, fmt.Errorf("Required 'step' key '%s' is missing", name)
 	}
 
-	// Try direct []string assertion first
-	if valueSlice, ok := value.([]string); ok {
-		return valueSlice, nil
+	result, err := toStringSlice(value)
+	if err != nil {
+		return nil, fmt.Errorf("Required 'step' key '%s' must be of type []string, %w", name, err)
 	}
 
-	// YAML unmarshaling produces []interface{} instead of []string,
-	// so we need to convert it manually
-	if interfaceSlice, ok := value.([]interface{}); ok {
-		result := make([]string, len(interfaceSlice))
-
-		for i, v := range interfaceSlice {
-			str, ok := v.(string)
-			if !ok {
-				return nil, fmt.Errorf("Required 'step' key '%s' must be of type []string, but element at index %d is %T", name, i, v)
-			}
-
-			result[i] = str
-		}
-
-		return result, nil
-	}
-
-	return nil, fmt.Errorf("Required 'step' key '%s' must be of type []string, got %T", name, value)
+	return result, nil
 }
 
 func (step ActionStep) RequiredString(name string) (string, error) {
@@ -254,6 +412,20 @@ func (step ActionStep) RequiredStringEnum(name string, values ...string) (string
 	return "", fmt.Errorf("Required 'step' key '%s' must be one of %v, got %s", name, values, valueString)
 }
 
+func (step ActionStep) OptionalBool(name string, fallback bool) (bool, error) {
+	value, ok := step[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	valueBool, ok := value.(bool)
+	if !ok {
+		return fallback, fmt.Errorf("Optional step field '%s' must be of type bool, got %T", name, value)
+	}
+
+	return valueBool, nil
+}
+
 func (step ActionStep) OptionalInt(name string, fallback int) (int, error) {
 	value, ok := step[name]
 	if !ok {
@@ -302,6 +474,58 @@ func (step ActionStep) OptionalStringEnum(name string, fallback string, values .
 	return fallback, fmt.Errorf("Optional step field '%s' must be one of %v, got %s", name, values, valueString)
 }
 
+func (step ActionStep) OptionalStringSlice(name string) ([]string, error) {
+	value, ok := step[name]
+	if !ok {
+		return nil, nil
+	}
+
+	result, err := toStringSlice(value)
+	if err != nil {
+		return nil, fmt.Errorf("Optional step field '%s' must be of type []string, %w", name, err)
+	}
+
+	return result, nil
+}
+
+// OptionalStringSliceMap reads a dictionary of string lists, for example:
+//
+//	teams:
+//	  backend: [alice, bob]
+//	  frontend: [charlie]
+func (step ActionStep) OptionalStringSliceMap(name string) (map[string][]string, error) {
+	value, ok := step[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var dictionary map[string]any
+
+	switch val := value.(type) {
+	case ActionStep:
+		dictionary = val
+
+	case map[string]any:
+		dictionary = val
+
+	default:
+		return nil, fmt.Errorf("Optional step field '%s' must be a dictionary of string lists, got %T", name, value)
+	}
+
+	result := make(map[string][]string, len(dictionary))
+
+	for key, item := range dictionary {
+		list, err := toStringSlice(item)
+		if err != nil {
+			return nil, fmt.Errorf("Optional step field '%s' key '%s' must be of type []string, %w", name, key, err)
+		}
+
+		result[key] = list
+	}
+
+	return result, nil
+}
+
 func (step ActionStep) Get(name string) (any, error) {
 	value, ok := step[name]
 	if !ok {
@@ -310,3 +534,29 @@ func (step ActionStep) Get(name string) (any, error) {
 
 	return value, nil
 }
+
+func toStringSlice(value any) ([]string, error) {
+	// Try direct []string assertion first
+	if valueSlice, ok := value.([]string); ok {
+		return valueSlice, nil
+	}
+
+	// YAML unmarshaling produces []interface{} instead of []string,
+	// so we need to convert it manually
+	if interfaceSlice, ok := value.([]interface{}); ok {
+		result := make([]string, len(interfaceSlice))
+
+		for i, v := range interfaceSlice {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("but element at index %d is %T", i, v)
+			}
+
+			result[i] = str
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("got %T", value)
+}
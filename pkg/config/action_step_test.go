@@ -169,6 +169,24 @@ func TestActionStep_OptionalString(t *testing.T) {
 	require.Equal(t, "fallback", got, "the fallback is returned alongside the error")
 }
 
+func TestActionStep_OptionalBool(t *testing.T) {
+	t.Parallel()
+
+	step := config.ActionStep{"present": true, "wrong-type": "yes"}
+
+	got, err := step.OptionalBool("present", false)
+	require.NoError(t, err)
+	require.True(t, got)
+
+	got, err = step.OptionalBool("missing", true)
+	require.NoError(t, err)
+	require.True(t, got)
+
+	got, err = step.OptionalBool("wrong-type", false)
+	require.ErrorContains(t, err, "must be of type bool, got string")
+	require.False(t, got)
+}
+
 func TestActionStep_OptionalInt(t *testing.T) {
 	t.Parallel()
 
@@ -206,6 +224,53 @@ func TestActionStep_OptionalStringEnum(t *testing.T) {
 	require.Equal(t, "fallback", got)
 }
 
+func TestActionStep_OptionalStringSlice(t *testing.T) {
+	t.Parallel()
+
+	step := config.ActionStep{"yaml": []any{"a", "b"}, "wrong-type": "nope"}
+
+	got, err := step.OptionalStringSlice("yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, got)
+
+	got, err = step.OptionalStringSlice("missing")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	_, err = step.OptionalStringSlice("wrong-type")
+	require.ErrorContains(t, err, "Optional step field 'wrong-type' must be of type []string, got string")
+}
+
+func TestActionStep_OptionalStringSliceMap(t *testing.T) {
+	t.Parallel()
+
+	step := config.ActionStep{
+		// Nested YAML dictionaries are decoded as ActionStep
+		"teams":       config.ActionStep{"backend": []any{"alice", "bob"}, "frontend": []string{"charlie"}},
+		"plain":       map[string]any{"docs": []any{"dave"}},
+		"not-a-map":   []any{"alice"},
+		"bad-members": config.ActionStep{"backend": "alice"},
+	}
+
+	got, err := step.OptionalStringSliceMap("teams")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"backend": {"alice", "bob"}, "frontend": {"charlie"}}, got)
+
+	got, err = step.OptionalStringSliceMap("plain")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"docs": {"dave"}}, got)
+
+	got, err = step.OptionalStringSliceMap("missing")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	_, err = step.OptionalStringSliceMap("not-a-map")
+	require.ErrorContains(t, err, "must be a dictionary of string lists, got []interface {}")
+
+	_, err = step.OptionalStringSliceMap("bad-members")
+	require.ErrorContains(t, err, "Optional step field 'bad-members' key 'backend' must be of type []string, got string")
+}
+
 func TestActionStep_Get(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/expr-lang/expr"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/jippi/scm-engine/pkg/stdlib"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// StoreValue applies the 'store_value' action step, persisting the output of the 'value'
+// expr-lang script under 'key' in the state of the current Merge Request
+func StoreValue(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) error {
+	key, err := step.RequiredString("key")
+	if err != nil {
+		return err
+	}
+
+	script, err := step.RequiredString("value")
+	if err != nil {
+		return err
+	}
+
+	options, err := compileOptions(ctx, evalContext, expr.AsAny())
+	if err != nil {
+		return err
+	}
+
+	program, err := expr.Compile(script, options...)
+	if err != nil {
+		return fmt.Errorf("could not compile 'value' for key '%s': %w", key, err)
+	}
+
+	value, err := expr.Run(program, evalContext)
+	if err != nil {
+		return fmt.Errorf("could not evaluate 'value' for key '%s': %w", key, err)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Storing value", slog.String("key", key), slog.Any("value", value))
+
+		return nil
+	}
+
+	return stdlib.SetStoredValue(ctx, key, value)
+}
+
+// DeleteStoredValue applies the 'delete_stored_value' action step, removing 'key' from the state of the current Merge Request
+func DeleteStoredValue(ctx context.Context, step scm.ActionStep) error {
+	key, err := step.RequiredString("key")
+	if err != nil {
+		return err
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Deleting stored value", slog.String("key", key))
+
+		return nil
+	}
+
+	return state.StoreFromContext(ctx).Delete(ctx, state.MergeRequestBucket(ctx), key)
+}
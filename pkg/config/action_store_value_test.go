@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/jippi/scm-engine/pkg/stdlib"
+	"github.com/stretchr/testify/require"
+)
+
+func storeContext(t *testing.T, dryRun bool) context.Context {
+	t.Helper()
+
+	ctx := state.WithStore(t.Context(), state.NewMemoryStore())
+	ctx = state.WithProvider(ctx, "gitlab")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+	ctx = state.WithDryRun(ctx, dryRun)
+
+	return ctx
+}
+
+func TestStoreValue(t *testing.T) {
+	t.Parallel()
+
+	ctx := storeContext(t, false)
+
+	evalContext := evalContext("bug")
+	evalContext.SetContext(ctx)
+
+	step := config.ActionStep{"action": "store_value", "key": "reminder_count", "value": `(stored_value("reminder_count") ?? 0) + 1`}
+
+	for range 2 {
+		require.NoError(t, config.StoreValue(ctx, evalContext, step))
+	}
+
+	value, found, err := stdlib.GetStoredValue(ctx, "reminder_count")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, 2, value)
+
+	require.NoError(t, config.DeleteStoredValue(ctx, config.ActionStep{"action": "delete_stored_value", "key": "reminder_count"}))
+
+	_, found, err = stdlib.GetStoredValue(ctx, "reminder_count")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestStoreValue_dryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := storeContext(t, true)
+
+	evalContext := evalContext()
+	evalContext.SetContext(ctx)
+
+	require.NoError(t, config.StoreValue(ctx, evalContext, config.ActionStep{"key": "warned_at", "value": "now()"}))
+
+	_, found, err := stdlib.GetStoredValue(ctx, "warned_at")
+	require.NoError(t, err)
+	require.False(t, found, "nothing must be stored in dry run mode")
+}
+
+func TestStoreValue_errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := storeContext(t, false)
+
+	require.EqualError(t, config.StoreValue(ctx, evalContext(), config.ActionStep{"value": "1"}), "Required 'step' key 'key' is missing")
+	require.EqualError(t, config.StoreValue(ctx, evalContext(), config.ActionStep{"key": "count"}), "Required 'step' key 'value' is missing")
+	require.ErrorContains(t, config.StoreValue(ctx, evalContext(), config.ActionStep{"key": "count", "value": "nope("}), "could not compile 'value' for key 'count'")
+	require.EqualError(t, config.DeleteStoredValue(ctx, config.ActionStep{}), "Required 'step' key 'key' is missing")
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+// UpdateDescription applies the 'update_description' action step, replacing each 'replace' key
+// in the Merge Request description with the output of its expr-lang script.
+//
+// The description is read from the update struct if an earlier step changed it, so multiple steps build on each other
+func UpdateDescription(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	// Use the raw MR description
+	body := evalContext.GetDescription()
+
+	// Unless something else already updated the description in the Update struct
+	if update.Description != nil {
+		body = *update.Description
+	}
+
+	replacements, err := step.Get("replace")
+	if err != nil {
+		return err
+	}
+
+	replacementSlice, ok := replacements.(ActionStep)
+	if !ok {
+		return fmt.Errorf(`step field 'replace' must be a dictionary with string key and string values ("key": "value"), got: %T`, replacements)
+	}
+
+	replacedAnything := false
+
+	for key, script := range replacementSlice {
+		// If the replacement key do not exist; we can skip the replacement logic entirely!
+		if !strings.Contains(body, key) {
+			continue
+		}
+
+		replacedAnything = true
+
+		options, err := compileOptions(ctx, evalContext, expr.AsKind(reflect.String))
+		if err != nil {
+			return err
+		}
+
+		program, err := expr.Compile(fmt.Sprintf("%s", script), options...)
+		if err != nil {
+			return fmt.Errorf("could not evaluate value for 'replace' key '%s': %w", key, err)
+		}
+
+		output, err := expr.Run(program, evalContext)
+		if err != nil {
+			return err
+		}
+
+		switch val := output.(type) {
+		case string:
+			body = strings.ReplaceAll(body, key, val)
+
+		default:
+			return fmt.Errorf("'replace' value for key '%s' did not return a string, got: %T", key, output)
+		}
+	}
+
+	// Don't update the body if there were no replacements
+	if !replacedAnything {
+		return nil
+	}
+
+	update.Description = &body
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+// UpdateTitle applies the 'update_title' action step, replacing the Merge Request title with the rendered 'title' template
+func UpdateTitle(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	title, err := step.RequiredString("title")
+	if err != nil {
+		return err
+	}
+
+	title, err = RenderTemplate(ctx, evalContext, title)
+	if err != nil {
+		return fmt.Errorf("could not render step field 'title': %w", err)
+	}
+
+	title = strings.TrimSpace(title)
+	if len(title) == 0 {
+		return errors.New("step field 'title' must not render an empty string")
+	}
+
+	// Don't update the title if it wouldn't change
+	if title == CurrentTitle(evalContext, update) {
+		return nil
+	}
+
+	update.Title = &title
+
+	return nil
+}
+
+// CurrentTitle returns the Merge Request title, or the title from the update struct if an earlier step changed it
+func CurrentTitle(evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions) string {
+	if update.Title != nil {
+		return *update.Title
+	}
+
+	return evalContext.GetTitle()
+}
@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		title   string
+		update  scm.UpdateMergeRequestOptions
+		want    *string
+		wantErr string
+	}{
+		{
+			name:  "renders the template",
+			title: "{{ upper(merge_request.title) }}",
+			want:  scm.Ptr("FIX THE BUG"),
+		},
+		{
+			name:  "unchanged title is not updated",
+			title: "  {{ merge_request.title }} ",
+			want:  nil,
+		},
+		{
+			name:   "builds on earlier steps",
+			title:  "{{ merge_request.title }}",
+			update: scm.UpdateMergeRequestOptions{Title: scm.Ptr("Draft: fix the bug")},
+			want:   scm.Ptr("fix the bug"),
+		},
+		{
+			name:    "empty title",
+			title:   "{{ '' }}",
+			wantErr: "step field 'title' must not render an empty string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := evalContext()
+			evalContext.MergeRequest.Title = "fix the bug"
+
+			step := config.ActionStep{"action": "update_title", "title": tt.title}
+
+			err := config.UpdateTitle(t.Context(), evalContext, &tt.update, step)
+			if len(tt.wantErr) > 0 {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tt.update.Title)
+		})
+	}
+}
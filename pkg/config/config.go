@@ -3,10 +3,12 @@ package config
 import (
 	"context"
 	"fmt"
-	"log/slog"
+	"maps"
+	"slices"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
 	slogctx "github.com/veqryn/slog-context"
 )
 
@@ -14,11 +16,21 @@ type Config struct {
 	// (Optional) When on, no actions will be taken, but instead logged for review
 	DryRun *bool `json:"dry_run,omitempty" yaml:"dry_run" jsonschema:"default=false"`
 
+	// (Optional) Named Expr Lang snippets, usable as variables in every script
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#definitions
+	Definitions Definitions `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+
 	// (Optional) Import configuration from other git repositories
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#include
 	Includes []Include `json:"include,omitempty" yaml:"include"`
 
+	// (Optional) Names of actions and labels to remove from the configuration, for example ones added by an include
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#disable
+	Disable []string `json:"disable,omitempty" yaml:"disable,omitempty"`
+
 	// (Optional) Configure what users that should be ignored when considering activity on a Merge Request
 	//
 	// SCM-Engine defines activity as comments, reviews, commits, adding/removing labels and similar actions made on a change request.
@@ -37,25 +49,69 @@ type Config struct {
 	Labels Labels `json:"label,omitempty" yaml:"label"`
 }
 
-func (c Config) Lint(_ context.Context, evalContext scm.EvalContext) error {
+func (c Config) Lint(ctx context.Context, evalContext scm.EvalContext) error {
 	var errors error
 
+	for _, include := range c.Includes {
+		if _, err := include.Type(); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Include failed validation: %w", err))
+		}
+	}
+
+	definitions, err := c.Definitions.Compile(evalContext)
+	if err != nil {
+		errors = multierror.Append(errors, fmt.Errorf("Definitions failed validation: %w", err))
+	}
+
+	ctx = withDefinitionOptions(ctx, definitions)
+
 	for _, action := range c.Actions {
-		if _, err := action.Setup(evalContext); err != nil {
+		if _, err := action.Setup(ctx, evalContext); err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("Action %q failed validation: %w", action.Name, err))
+		}
+
+		if _, err := action.Duration(); err != nil {
 			errors = multierror.Append(errors, fmt.Errorf("Action %q failed validation: %w", action.Name, err))
 		}
+
+		if len(action.For) > 0 && !state.IsPersistentStore(ctx) {
+			slogctx.Warn(ctx, fmt.Sprintf("Action %q uses 'for', but the state store isn't persistent (see --state-store), so the timer restarts on every run of scm-engine and the action never runs on one-shot evaluations", action.Name))
+		}
+
+		for _, step := range slices.Concat(action.Then, action.Else) {
+			if _, err := step.OnError(); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Action %q failed validation: %w", action.Name, err))
+			}
+
+			if err := step.lintTemplates(ctx, evalContext); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Action %q failed validation: %w", action.Name, err))
+			}
+
+			if err := step.lintDiscussion(ctx); err != nil {
+				errors = multierror.Append(errors, fmt.Errorf("Action %q failed validation: %w", action.Name, err))
+			}
+		}
 	}
 
 	for _, label := range c.Labels {
-		if err := label.Setup(evalContext); err != nil {
+		if err := label.Setup(ctx, evalContext); err != nil {
 			errors = multierror.Append(errors, fmt.Errorf("Label %q failed validation: %w", label.Name, err))
 		}
 	}
 
+	for _, group := range c.Labels.ExclusiveGroupsWithoutFallback(ctx) {
+		slogctx.Warn(ctx, fmt.Sprintf("Exclusive label group %q has no fallback label (script: 'true'), so a Merge Request may end up without any label from the group", group))
+	}
+
 	return errors
 }
 
 func (c Config) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]scm.EvaluationResult, []Action, error) {
+	ctx, err := c.WithDefinitions(ctx, evalContext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("evaluation failed: %w", err)
+	}
+
 	slogctx.Info(ctx, "Evaluating labels")
 
 	labels, err := c.Labels.Evaluate(ctx, evalContext)
@@ -73,63 +129,6 @@ func (c Config) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]sc
 	return labels, actions, nil
 }
 
-func (c *Config) LoadIncludes(ctx context.Context, client scm.Client) error {
-	// No files to include
-	if len(c.Includes) == 0 {
-		return nil
-	}
-
-	// Update logger with a friendly tag to differentiate the events within
-	ctx = slogctx.With(ctx, slog.String("phase", "remote_include"))
-
-	// For each project, do a read of all the files we need
-	for _, include := range c.Includes {
-		ctx := slogctx.With(ctx, slog.Any("remote_include_config", include))
-
-		slogctx.Debug(ctx, fmt.Sprintf("Loading remote configuration from project %q", include.Project))
-
-		files, err := client.GetProjectFiles(ctx, include.Project, include.Ref, include.Files)
-		if err != nil {
-			return fmt.Errorf("failed to load included config files from project [%s]: %w", include.Project, err)
-		}
-
-		for fileName, fileContent := range files {
-			remoteConfig, err := ParseFileString(fileContent)
-			if err != nil {
-				return fmt.Errorf("failed to parse remote config file [%s] from project [%s]: %w", fileName, include.Project, err)
-			}
-
-			// Disallow nested includes
-			if len(remoteConfig.Includes) != 0 {
-				slogctx.Warn(ctx, fmt.Sprintf("file [%s] from project [%s] may not have any 'include' settings; Recursive include is not supported", fileName, include.Project))
-			}
-
-			// Disallow changing dry run
-			if remoteConfig.DryRun != nil {
-				slogctx.Warn(ctx, fmt.Sprintf("file [%s] from project [%s] may not have a 'dry_run' setting; Remote include are not allowed to change this setting", fileName, include.Project))
-			}
-
-			// Append actions
-			if len(remoteConfig.Actions) != 0 {
-				slogctx.Debug(ctx, fmt.Sprintf("file [%s] from project [%s] added %d new actions to the config file", fileName, include.Project, len(remoteConfig.Actions)))
-
-				c.Actions = append(c.Actions, remoteConfig.Actions...)
-			}
-
-			// Append labels
-			if len(remoteConfig.Labels) != 0 {
-				slogctx.Debug(ctx, fmt.Sprintf("file [%s] from project [%s] added %d new labels to the config file", fileName, include.Project, len(remoteConfig.Labels)))
-
-				c.Labels = append(c.Labels, remoteConfig.Labels...)
-			}
-		}
-	}
-
-	slogctx.Debug(ctx, "Done loading remote configuration files")
-
-	return nil
-}
-
 // Merge merges the other config into the current config
 func (c *Config) Merge(other *Config) *Config {
 	cfg := &Config{}
@@ -137,10 +136,12 @@ func (c *Config) Merge(other *Config) *Config {
 	if other == nil {
 		return &Config{
 			DryRun:             c.DryRun,
+			Definitions:        c.Definitions,
 			IgnoreActivityFrom: c.IgnoreActivityFrom,
 			Actions:            c.Actions,
 			Labels:             c.Labels,
 			Includes:           c.Includes,
+			Disable:            c.Disable,
 		}
 	}
 
@@ -160,6 +161,14 @@ func (c *Config) Merge(other *Config) *Config {
 		})
 	}
 
+	// Definitions in the other config replaces definitions with the same name
+	if c.Definitions != nil || other.Definitions != nil {
+		cfg.Definitions = make(Definitions, len(c.Definitions)+len(other.Definitions))
+
+		maps.Copy(cfg.Definitions, c.Definitions)
+		maps.Copy(cfg.Definitions, other.Definitions)
+	}
+
 	if c.Actions != nil || other.Actions != nil {
 		cfg.Actions = scm.MergeSlices(c.Actions, other.Actions, func(action Action) string {
 			return action.Name
@@ -172,7 +181,14 @@ func (c *Config) Merge(other *Config) *Config {
 		})
 	}
 
-	// Merge includes, but skip adding duplicate files under a project/ref.
+	if c.Disable != nil || other.Disable != nil {
+		cfg.Disable = scm.MergeSlices(c.Disable, other.Disable, func(name string) string {
+			return name
+		})
+	}
+
+	// Merge includes, but skip adding duplicate files under a project/ref, local
+	// files and URLs.
 	//
 	// Both the includes and the files within them keep first-seen order: the
 	// maps below are only used for de-duplication, never for iteration, since
@@ -185,20 +201,28 @@ func (c *Config) Merge(other *Config) *Config {
 
 		for _, includes := range [][]Include{c.Includes, other.Includes} {
 			for _, include := range includes {
-				mapKey := key(include.Project, include.Ref)
+				mapKey, files := key(include.Project, include.Ref), include.Files
+
+				switch {
+				case len(include.Local) > 0:
+					mapKey, files = "local:", include.Local
+
+				case len(include.URL) > 0:
+					mapKey = "url:" + include.URL
+				}
 
 				entry, ok := merge[mapKey]
 				if !ok {
 					entry = &mergedInclude{
-						project:   include.Project,
-						ref:       include.Ref,
-						seenFiles: make(map[string]struct{}, len(include.Files)),
+						include:   Include{Project: include.Project, Ref: include.Ref, URL: include.URL, SHA256: include.SHA256},
+						local:     len(include.Local) > 0,
+						seenFiles: make(map[string]struct{}, len(files)),
 					}
 					merge[mapKey] = entry
 					order = append(order, mapKey)
 				}
 
-				for _, file := range include.Files {
+				for _, file := range files {
 					if _, seen := entry.seenFiles[file]; seen {
 						continue
 					}
@@ -214,22 +238,25 @@ func (c *Config) Merge(other *Config) *Config {
 		for _, mapKey := range order {
 			entry := merge[mapKey]
 
-			cfg.Includes = append(cfg.Includes, Include{
-				Project: entry.project,
-				Ref:     entry.ref,
-				Files:   entry.files,
-			})
+			include := entry.include
+			if entry.local {
+				include.Local = entry.files
+			} else {
+				include.Files = entry.files
+			}
+
+			cfg.Includes = append(cfg.Includes, include)
 		}
 	}
 
 	return cfg
 }
 
-// mergedInclude accumulates the files seen for a single project/ref pair while
+// mergedInclude accumulates the files seen for a single include source while
 // merging two configurations, keeping the files in the order they were seen.
 type mergedInclude struct {
-	project   string
-	ref       *string
+	include   Include
+	local     bool
 	files     []string
 	seenFiles map[string]struct{}
 }
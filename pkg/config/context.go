@@ -2,6 +2,9 @@ package config
 
 import (
 	"context"
+
+	"github.com/expr-lang/expr"
+	"github.com/jippi/scm-engine/pkg/scm"
 )
 
 type contextKey uint
@@ -9,6 +12,7 @@ type contextKey uint
 const (
 	configKey contextKey = iota
 	globalConfigKey
+	definitionsKey
 )
 
 func WithConfig(ctx context.Context, config *Config) context.Context {
@@ -24,5 +28,30 @@ func WithGlobalConfig(ctx context.Context, config *Config) context.Context {
 }
 
 func GlobalConfigFromContext(ctx context.Context) *Config {
-	return ctx.Value(globalConfigKey).(*Config) //nolint:forcetypeassert
+	// The global config is optional, so it's not an error for it to be missing
+	cfg, _ := ctx.Value(globalConfigKey).(*Config)
+
+	return cfg
+}
+
+// withDefinitionOptions stores the compiled definitions, so scripts compiled within the context can use them
+func withDefinitionOptions(ctx context.Context, options []expr.Option) context.Context {
+	return context.WithValue(ctx, definitionsKey, options)
+}
+
+// WithDefinitions compiles the definitions and stores them in the context, so scripts compiled within the
+// context (including the steps applied after the evaluation) don't compile them again.
+//
+// The context is returned as-is if it already has the compiled definitions
+func (c Config) WithDefinitions(ctx context.Context, evalContext scm.EvalContext) (context.Context, error) {
+	if _, ok := ctx.Value(definitionsKey).([]expr.Option); ok {
+		return ctx, nil
+	}
+
+	definitions, err := c.Definitions.Compile(evalContext)
+	if err != nil {
+		return ctx, err
+	}
+
+	return withDefinitionOptions(ctx, definitions), nil
 }
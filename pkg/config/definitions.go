@@ -0,0 +1,245 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/conf"
+	"github.com/expr-lang/expr/parser"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/stdlib"
+)
+
+// Definitions are named Expr Lang snippets, usable as variables in every script.
+//
+// The key is the name of the variable, and the value is the Expr Lang script.
+//
+// See: https://jippi.github.io/scm-engine/configuration/#definitions
+type Definitions map[string]string
+
+var definitionNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Compile compiles every definition against the evaluation context, returning the
+// expr-lang options that make them available to other scripts.
+//
+// Definitions may reference each other, but not in a cycle
+func (d Definitions) Compile(evalContext scm.EvalContext) ([]expr.Option, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+
+	if err := d.checkNames(evalContext); err != nil {
+		return nil, err
+	}
+
+	order, err := d.order()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		patcher = &definitionPatcher{names: map[string]bool{}}
+		options = []expr.Option{expr.Patch(patcher)}
+	)
+
+	for _, name := range order {
+		program, err := expr.Compile(d[name], append(stdlib.CompileOptions(evalContext, expr.AsAny()), options...)...)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile definition %q into valid expr-lang syntax: %w", name, err)
+		}
+
+		// Type the function by the output of the script, so scripts using it are type checked too
+		outputType := reflect.TypeFor[any]()
+		if nodeType := program.Node().Type(); nodeType != nil {
+			outputType = nodeType
+		}
+
+		signature := reflect.Zero(reflect.FuncOf([]reflect.Type{reflect.TypeFor[any]()}, []reflect.Type{outputType}, false)).Interface()
+
+		options = append(options, expr.Function(name, func(params ...any) (any, error) {
+			return expr.Run(program, params[0])
+		}, signature))
+
+		patcher.names[name] = true
+	}
+
+	return options, nil
+}
+
+// checkNames rejects definitions named like a field of the evaluation context or a function, since every
+// reference to the name (e.g. 'merge_request.title') would be replaced by the definition
+func (d Definitions) checkNames(evalContext scm.EvalContext) error {
+	env := conf.CreateNew()
+
+	for _, option := range stdlib.CompileOptions(evalContext, expr.AsAny()) {
+		option(env)
+	}
+
+	var errs error
+
+	for _, name := range slices.Sorted(maps.Keys(d)) {
+		if _, ok := env.Env.Get(&env.NtCache, name); ok {
+			errs = errors.Join(errs, fmt.Errorf("definition name %q is already a field of the evaluation context", name))
+
+			continue
+		}
+
+		if env.Functions[name] != nil || env.Builtins[name] != nil {
+			errs = errors.Join(errs, fmt.Errorf("definition name %q is already a function", name))
+		}
+	}
+
+	return errs
+}
+
+// order returns the definition names sorted so every definition comes after the definitions it references
+func (d Definitions) order() ([]string, error) {
+	dependencies := make(map[string][]string, len(d))
+
+	for _, name := range slices.Sorted(maps.Keys(d)) {
+		if !definitionNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("definition name %q is invalid, it must be a valid variable name (letters, digits and underscores)", name)
+		}
+
+		tree, err := parser.Parse(d[name])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse definition %q: %w", name, err)
+		}
+
+		collector := &identifierCollector{names: d}
+		ast.Walk(&tree.Node, collector)
+
+		dependencies[name] = collector.found
+	}
+
+	var (
+		order   []string
+		visited = map[string]bool{}
+		visit   func(name string, path []string) error
+	)
+
+	visit = func(name string, path []string) error {
+		if slices.Contains(path, name) {
+			return fmt.Errorf("definition %q has a cyclic reference: %s", name, strings.Join(append(path, name), " -> "))
+		}
+
+		if visited[name] {
+			return nil
+		}
+
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		visited[name] = true
+		order = append(order, name)
+
+		return nil
+	}
+
+	var errs error
+
+	for _, name := range slices.Sorted(maps.Keys(d)) {
+		if err := visit(name, nil); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return order, errs
+}
+
+// identifierCollector collects the names of definitions referenced by a script
+type identifierCollector struct {
+	names Definitions
+	found []string
+}
+
+func (c *identifierCollector) Visit(node *ast.Node) {
+	if identifier, ok := (*node).(*ast.IdentifierNode); ok {
+		if _, ok := c.names[identifier.Value]; ok && !slices.Contains(c.found, identifier.Value) {
+			c.found = append(c.found, identifier.Value)
+		}
+	}
+}
+
+// definitionPatcher rewrites a definition used as a variable (e.g. 'is_docs_only') or called as a function
+// (e.g. 'is_docs_only()') into a call of its function with the environment (e.g. 'is_docs_only($env)'),
+// so it's evaluated against the same context
+type definitionPatcher struct {
+	names map[string]bool
+}
+
+func (p *definitionPatcher) Visit(node *ast.Node) {
+	switch current := (*node).(type) {
+	case *ast.IdentifierNode:
+		if !p.names[current.Value] {
+			return
+		}
+
+		ast.Patch(node, &ast.CallNode{
+			Callee:    &ast.IdentifierNode{Value: current.Value},
+			Arguments: []ast.Node{&ast.IdentifierNode{Value: "$env"}},
+		})
+
+	case *ast.CallNode:
+		// The callee is visited first, so 'is_docs_only()' has become 'is_docs_only($env)()' by now.
+		// Unwrap it into a new node (without the types checked before patching), any arguments are
+		// kept so the call fails type checking
+		callee, ok := current.Callee.(*ast.CallNode)
+		if !ok || !p.isDefinitionCall(callee) {
+			return
+		}
+
+		ast.Patch(node, &ast.CallNode{
+			Callee:    callee.Callee,
+			Arguments: slices.Concat(callee.Arguments, current.Arguments),
+		})
+	}
+}
+
+// isDefinitionCall returns whether the node is a call created by the patcher
+func (p *definitionPatcher) isDefinitionCall(call *ast.CallNode) bool {
+	identifier, ok := call.Callee.(*ast.IdentifierNode)
+	if !ok || !p.names[identifier.Value] || len(call.Arguments) != 1 {
+		return false
+	}
+
+	env, ok := call.Arguments[0].(*ast.IdentifierNode)
+
+	return ok && env.Value == "$env"
+}
+
+// compileOptions returns the options for compiling a script, including the definitions of the configuration.
+//
+// The definitions compiled by [Config.Lint] and [Config.Evaluate] are used if available, otherwise the
+// definitions of the configuration in the context (if any) are compiled
+func compileOptions(ctx context.Context, evalContext scm.EvalContext, returnType expr.Option) ([]expr.Option, error) {
+	options := stdlib.CompileOptions(evalContext, returnType)
+
+	if definitions, ok := ctx.Value(definitionsKey).([]expr.Option); ok {
+		return append(options, definitions...), nil
+	}
+
+	// The config is optional, for example in tests evaluating a single label
+	cfg, _ := ctx.Value(configKey).(*Config)
+	if cfg == nil {
+		return options, nil
+	}
+
+	definitions, err := cfg.Definitions.Compile(evalContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, definitions...), nil
+}
@@ -0,0 +1,176 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitions(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.ParseFileString(`
+definitions:
+  is_bug: merge_request.has_label("bug")
+  is_urgent_bug: is_bug && merge_request.has_label("urgent")
+
+label:
+  - name: urgent-bug
+    script: is_urgent_bug
+  - name: not-a-bug
+    script: not is_bug
+  - name: called-bug
+    script: is_bug() && is_urgent_bug()
+
+actions:
+  - name: escalate
+    if: is_urgent_bug
+    then:
+      - action: comment
+        message: Escalating
+`)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Lint(t.Context(), evalContext()))
+
+	labels, actions, err := cfg.Evaluate(t.Context(), evalContext("bug", "urgent"))
+	require.NoError(t, err)
+
+	require.Len(t, labels, 3)
+	require.True(t, labels[0].Matched)
+	require.False(t, labels[1].Matched)
+	require.True(t, labels[2].Matched)
+
+	require.Len(t, actions, 1)
+	require.Equal(t, "escalate", actions[0].Name)
+}
+
+// Scripts using a definition are type checked against its output
+func TestDefinitions_typed(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{
+		Definitions: config.Definitions{"bug_label": `"bug"`},
+		Labels:      config.Labels{{Name: "x", Script: `bug_label`}},
+	}
+
+	require.ErrorContains(t, cfg.Lint(t.Context(), evalContext()), "expected bool, but got string")
+}
+
+func TestDefinitions_lint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		definitions config.Definitions
+		wantErr     string
+	}{
+		{
+			name:        "unknown reference",
+			definitions: config.Definitions{"a": `is_bugg`},
+			wantErr:     `could not compile definition "a"`,
+		},
+		{
+			name:        "cyclic reference",
+			definitions: config.Definitions{"a": `b`, "b": `c && true`, "c": `a`},
+			wantErr:     `definition "a" has a cyclic reference: a -> b -> c -> a`,
+		},
+		{
+			name:        "self reference",
+			definitions: config.Definitions{"a": `a || true`},
+			wantErr:     `definition "a" has a cyclic reference: a -> a`,
+		},
+		{
+			name:        "invalid name",
+			definitions: config.Definitions{"is-bug": `true`},
+			wantErr:     `definition name "is-bug" is invalid`,
+		},
+		{
+			name:        "called with arguments",
+			definitions: config.Definitions{"a": `true`, "b": `a(1)`},
+			wantErr:     `could not compile definition "b"`,
+		},
+		{
+			name:        "name of a context field",
+			definitions: config.Definitions{"merge_request": `true`},
+			wantErr:     `definition name "merge_request" is already a field of the evaluation context`,
+		},
+		{
+			name:        "name of a builtin function",
+			definitions: config.Definitions{"len": `1`},
+			wantErr:     `definition name "len" is already a function`,
+		},
+		{
+			name:        "name of a stdlib function",
+			definitions: config.Definitions{"uniq": `1`},
+			wantErr:     `definition name "uniq" is already a function`,
+		},
+		{
+			name:        "invalid syntax",
+			definitions: config.Definitions{"a": `(`},
+			wantErr:     `could not parse definition "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.Config{Definitions: tt.definitions}
+
+			err := cfg.Lint(t.Context(), evalContext())
+			require.ErrorContains(t, err, "Definitions failed validation")
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// Actions compiling scripts at apply time read the definitions from the configuration in the context
+func TestDefinitions_fromContext(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Definitions: config.Definitions{"greeting": `"hello"`}}
+	ctx := config.WithConfig(t.Context(), cfg)
+
+	label := &config.Label{Name: "x", Script: `greeting == "hello"`}
+
+	results, err := label.Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.True(t, results[0].Matched)
+}
+
+// The compiled definitions are kept in the context, so steps applied later don't compile them again
+func TestConfig_WithDefinitions(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Definitions: config.Definitions{"greeting": `"hello"`}}
+
+	ctx, err := cfg.WithDefinitions(t.Context(), evalContext())
+	require.NoError(t, err)
+
+	// Invalid definitions in the context config are never compiled, since the compiled ones are used
+	ctx = config.WithConfig(ctx, &config.Config{Definitions: config.Definitions{"greeting": `(`}})
+
+	label := &config.Label{Name: "x", Script: `greeting == "hello"`}
+
+	results, err := label.Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.True(t, results[0].Matched)
+
+	// Already compiled definitions are kept
+	same, err := cfg.WithDefinitions(ctx, evalContext())
+	require.NoError(t, err)
+	require.Equal(t, ctx, same)
+
+	_, err = (&config.Config{Definitions: config.Definitions{"merge_request": `true`}}).WithDefinitions(t.Context(), evalContext())
+	require.EqualError(t, err, `definition name "merge_request" is already a field of the evaluation context`)
+}
+
+func TestConfig_Merge_definitions(t *testing.T) {
+	t.Parallel()
+
+	global := &config.Config{Definitions: config.Definitions{"a": `true`, "b": `true`}}
+	local := &config.Config{Definitions: config.Definitions{"b": `false`, "c": `false`}}
+
+	require.Equal(t, config.Definitions{"a": `true`, "b": `false`, "c": `false`}, global.Merge(local).Definitions)
+}
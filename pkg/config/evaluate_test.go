@@ -2,10 +2,12 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jippi/scm-engine/pkg/config"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/stdlib"
 	"github.com/jippi/scm-engine/pkg/types"
 	"github.com/stretchr/testify/require"
 )
@@ -136,6 +138,71 @@ func TestLabel_Evaluate_generate(t *testing.T) {
 	}
 }
 
+// Existing labels owned by a 'generate' label, but no longer generated, are reported as unmatched so they get removed
+func TestLabel_Evaluate_generateOwnedLabels(t *testing.T) {
+	t.Parallel()
+
+	existing := evalContext("component/api", "component/legacy", "area/legacy", "bug")
+
+	tests := []struct {
+		name  string
+		label config.Label
+		want  map[string]bool
+	}{
+		{
+			name:  "without ownership nothing is removed",
+			label: config.Label{Script: `["component/api"]`},
+			want:  map[string]bool{"component/api": true},
+		},
+		{
+			name:  "owned_prefix",
+			label: config.Label{Script: `["component/api", "component/web"]`, OwnedPrefix: "component/"},
+			want:  map[string]bool{"component/api": true, "component/web": true, "component/legacy": false},
+		},
+		{
+			name:  "owned_regex",
+			label: config.Label{Script: `[]`, OwnedRegex: "^(component|area)/"},
+			want:  map[string]bool{"component/api": false, "component/legacy": false, "area/legacy": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			label := tt.label
+			label.Strategy = config.GenerateLabels
+			label.Color = "#FF0000"
+			label.Description = "generated"
+
+			results, err := label.Evaluate(t.Context(), existing)
+			require.NoError(t, err)
+
+			got := map[string]bool{}
+			for _, result := range results {
+				got[result.Name] = result.Matched
+
+				// Stale labels keep their own color and description
+				if !result.Matched {
+					require.Equal(t, scm.EvaluationResult{Name: result.Name, Stale: true}, result)
+				}
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLabel_Setup_ownedLabels(t *testing.T) {
+	t.Parallel()
+
+	label := &config.Label{Name: "x", Script: `true`, OwnedPrefix: "component/"}
+	require.ErrorContains(t, label.Setup(t.Context(), evalContext()), "may only be specified when using [type: \"generate\"]")
+
+	label = &config.Label{Script: `[]`, Strategy: config.GenerateLabels, OwnedRegex: "component/("}
+	require.ErrorContains(t, label.Setup(t.Context(), evalContext()), "could not compile 'owned_regex'")
+}
+
 // The strategy and the script return type have to agree. The mismatch is caught
 // while compiling the script, because the expected return type is baked into the
 // compile options, so it surfaces during `scm-engine lint` rather than half way
@@ -252,6 +319,26 @@ func TestLabels_Evaluate_rejectsDuplicateNames(t *testing.T) {
 	require.ErrorContains(t, err, "was generated multiple times")
 }
 
+// A stale owned label that another label still emits is not removed
+func TestLabels_Evaluate_ownedLabelEmittedElsewhere(t *testing.T) {
+	t.Parallel()
+
+	labels := config.Labels{
+		{Script: `[]`, Strategy: config.GenerateLabels, OwnedPrefix: "component/"},
+		{Name: "component/core", Script: `true`, Strategy: config.ConditionalLabel},
+	}
+
+	results, err := labels.Evaluate(t.Context(), evalContext("component/core", "component/legacy"))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "component/core", results[0].Name)
+	require.True(t, results[0].Matched)
+
+	require.Equal(t, "component/legacy", results[1].Name)
+	require.False(t, results[1].Matched)
+}
+
 func TestLabels_Evaluate_rejectsEmptyName(t *testing.T) {
 	t.Parallel()
 
@@ -334,6 +421,77 @@ func TestActions_Evaluate_noneMatching(t *testing.T) {
 	require.Empty(t, results)
 }
 
+// Actions with 'else' steps are kept when they evaluate negatively, with the 'else' steps selected
+func TestActions_Evaluate_elseBranch(t *testing.T) {
+	t.Parallel()
+
+	actions := config.Actions{
+		{Name: "yes", If: `true`, Then: []config.ActionStep{{"action": "approve"}}, Else: []config.ActionStep{{"action": "close"}}},
+		{Name: "no", If: `false`, Then: []config.ActionStep{{"action": "approve"}}, Else: []config.ActionStep{{"action": "close"}}},
+	}
+
+	results, err := actions.Evaluate(t.Context(), evalContext())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "then", results[0].Branch())
+	require.Equal(t, []config.ActionStep{{"action": "approve"}}, results[0].Steps())
+
+	require.Equal(t, "else", results[1].Branch())
+	require.Equal(t, []config.ActionStep{{"action": "close"}}, results[1].Steps())
+}
+
+func TestActions_Evaluate_for(t *testing.T) {
+	t.Parallel()
+
+	ctx := storeContext(t, false)
+
+	actions := func(script string) config.Actions {
+		return config.Actions{{Name: "close", If: script, For: "3d", Then: []config.ActionStep{{"action": "close"}}}}
+	}
+
+	// The first time the condition is true the timer starts, but the action is not executed yet
+	results, err := actions(`true`).Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	since, found, err := stdlib.GetStoredValue(ctx, "action_condition_since:close")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.WithinDuration(t, time.Now(), since.(time.Time), time.Minute) //nolint:forcetypeassert
+
+	// Once the condition has been true for long enough, the action is executed
+	require.NoError(t, stdlib.SetStoredValue(ctx, "action_condition_since:close", time.Now().Add(-73*time.Hour)))
+
+	results, err = actions(`true`).Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// The timer is reset when the condition is false
+	results, err = actions(`false`).Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	_, found, err = stdlib.GetStoredValue(ctx, "action_condition_since:close")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestActions_Evaluate_forDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := storeContext(t, true)
+
+	results, err := config.Actions{{Name: "close", If: `true`, For: "1h"}}.Evaluate(ctx, evalContext())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// Nothing is persisted in dry run mode
+	_, found, err := stdlib.GetStoredValue(ctx, "action_condition_since:close")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
 func TestActions_Evaluate_propagatesError(t *testing.T) {
 	t.Parallel()
 
@@ -404,6 +562,28 @@ func TestConfig_Lint(t *testing.T) {
 		require.ErrorContains(t, cfg.Lint(t.Context(), evalContext()), `Action "broken" failed validation`)
 	})
 
+	t.Run("an unknown on_error policy is reported", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{Actions: config.Actions{{
+			Name: "flaky",
+			If:   `true`,
+			Else: []config.ActionStep{{"action": "comment", "on_error": "ignore"}},
+		}}}
+
+		err := cfg.Lint(t.Context(), evalContext())
+		require.ErrorContains(t, err, `Action "flaky" failed validation`)
+		require.ErrorContains(t, err, "on_error")
+	})
+
+	t.Run("an invalid for duration is reported", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{Actions: config.Actions{{Name: "close", If: `true`, For: "three days"}}}
+
+		require.ErrorContains(t, cfg.Lint(t.Context(), evalContext()), `Action "close" failed validation: invalid 'for' duration "three days"`)
+	})
+
 	// Lint collects every problem so a user fixes them in one pass.
 	t.Run("every problem is reported", func(t *testing.T) {
 		t.Parallel()
@@ -1,15 +1,33 @@
 package config
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// The kinds of include sources, each one is read by an [IncludeResolver]
+const (
+	IncludeTypeProject = "project"
+	IncludeTypeLocal   = "local"
+	IncludeTypeURL     = "url"
+)
+
 type Include struct {
 	// The project to include files from
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#include.project
-	Project string `json:"project" yaml:"project"`
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
 
 	// The list of files to include from the project. The paths must be relative to the repository root, e.x. label/some-config-file.yml; NOT /label/some-config-file.yml
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#include.files
-	Files []string `json:"files" yaml:"files"`
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
 
 	// (Optional) Git reference to read the configuration from; it can be a tag, branch, or commit SHA.
 	//
@@ -17,4 +35,179 @@ type Include struct {
 	//
 	// See: https://jippi.github.io/scm-engine/configuration/#include.ref
 	Ref *string `json:"ref,omitempty" yaml:"ref"`
+
+	// The list of files to include from the same repository as the configuration file. The paths must be relative to the repository root
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#include.local
+	Local []string `json:"local,omitempty" yaml:"local,omitempty"`
+
+	// The HTTPS URL of a configuration file to include
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#include.url
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// (Optional) The expected SHA256 checksum (hex) of the file at [url]
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#include.sha256
+	SHA256 string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+}
+
+// Type returns the kind of include source, exactly one of 'project', 'local' or 'url' must be set
+func (i Include) Type() (string, error) {
+	var types []string
+
+	if len(i.Project) > 0 {
+		types = append(types, IncludeTypeProject)
+	}
+
+	if len(i.Local) > 0 {
+		types = append(types, IncludeTypeLocal)
+	}
+
+	if len(i.URL) > 0 {
+		types = append(types, IncludeTypeURL)
+	}
+
+	switch len(types) {
+	case 1:
+		return types[0], nil
+
+	case 0:
+		return "", errors.New("include must have one of 'project', 'local' or 'url'")
+
+	default:
+		return "", fmt.Errorf("include may only have one of 'project', 'local' or 'url', got %s", strings.Join(types, ", "))
+	}
+}
+
+// relativeTo resolves a 'local' include found in a file read by the parent include, so it reads from the same source
+func (i Include) relativeTo(parent Include) (Include, error) {
+	if len(i.Local) == 0 || len(parent.Local) > 0 {
+		return i, nil
+	}
+
+	if len(parent.Project) > 0 {
+		return Include{Project: parent.Project, Ref: parent.Ref, Files: i.Local}, nil
+	}
+
+	return i, fmt.Errorf("'local' includes are not supported in files included from %s", parent.URL)
+}
+
+// MaxIncludeDepth is how deep includes may be nested, an include in the repository configuration is depth 1
+const MaxIncludeDepth = 5
+
+// LoadIncludes loads the configuration files in 'include', recursively, and adds their definitions, actions and labels.
+//
+// The including configuration wins when both define an action or label with the same name, and
+// the names in 'disable' are removed once all includes are loaded.
+func (c *Config) LoadIncludes(ctx context.Context, resolvers IncludeResolvers) error {
+	// Update logger with a friendly tag to differentiate the events within
+	ctx = slogctx.With(ctx, slog.String("phase", "remote_include"))
+
+	if err := c.loadIncludes(ctx, resolvers, nil); err != nil {
+		return err
+	}
+
+	slogctx.Debug(ctx, "Done loading remote configuration files")
+
+	return nil
+}
+
+func (c *Config) loadIncludes(ctx context.Context, resolvers IncludeResolvers, chain []string) error {
+	if len(c.Includes) > 0 && len(chain) >= MaxIncludeDepth {
+		return fmt.Errorf("includes may not be nested more than %d levels deep: %s", MaxIncludeDepth, strings.Join(chain, " -> "))
+	}
+
+	for _, include := range c.Includes {
+		ctx := slogctx.With(ctx, slog.Any("remote_include_config", include))
+
+		files, err := resolvers.Resolve(ctx, include)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if slices.Contains(chain, file.ID) {
+				return fmt.Errorf("include cycle detected: %s", strings.Join(append(chain, file.ID), " -> "))
+			}
+
+			remoteConfig, err := ParseFileString(file.Content)
+			if err != nil {
+				return fmt.Errorf("failed to parse remote config file [%s]: %w", file.ID, err)
+			}
+
+			// Disallow changing dry run
+			if remoteConfig.DryRun != nil {
+				slogctx.Warn(ctx, fmt.Sprintf("file [%s] may not have a 'dry_run' setting; Remote include are not allowed to change this setting", file.ID))
+			}
+
+			for idx, nested := range remoteConfig.Includes {
+				if remoteConfig.Includes[idx], err = nested.relativeTo(include); err != nil {
+					return fmt.Errorf("failed to load remote config file [%s]: %w", file.ID, err)
+				}
+			}
+
+			if err := remoteConfig.loadIncludes(ctx, resolvers, append(slices.Clone(chain), file.ID)); err != nil {
+				return err
+			}
+
+			c.addIncluded(ctx, fmt.Sprintf("file [%s]", file.ID), remoteConfig)
+		}
+	}
+
+	c.ApplyDisable(ctx)
+
+	return nil
+}
+
+// addIncluded adds the definitions, actions and labels of an included configuration, unless already defined
+func (c *Config) addIncluded(ctx context.Context, source string, remote *Config) {
+	for name, script := range remote.Definitions {
+		if _, ok := c.Definitions[name]; ok {
+			slogctx.Debug(ctx, fmt.Sprintf("%s definition %q is already defined, ignoring it", source, name))
+
+			continue
+		}
+
+		if c.Definitions == nil {
+			c.Definitions = Definitions{}
+		}
+
+		c.Definitions[name] = script
+	}
+
+	for _, action := range remote.Actions {
+		if slices.ContainsFunc(c.Actions, func(existing Action) bool { return existing.Name == action.Name }) {
+			slogctx.Debug(ctx, fmt.Sprintf("%s action %q is overridden by the including configuration", source, action.Name))
+
+			continue
+		}
+
+		c.Actions = append(c.Actions, action)
+	}
+
+	for _, label := range remote.Labels {
+		// Labels using the 'generate' strategy has no name, so they can't be overridden
+		if len(label.Name) > 0 && slices.ContainsFunc(c.Labels, func(existing *Label) bool { return existing.Name == label.Name }) {
+			slogctx.Debug(ctx, fmt.Sprintf("%s label %q is overridden by the including configuration", source, label.Name))
+
+			continue
+		}
+
+		c.Labels = append(c.Labels, label)
+	}
+}
+
+// ApplyDisable removes the actions and labels listed in 'disable', it's done by [Config.LoadIncludes] as well
+func (c *Config) ApplyDisable(ctx context.Context) {
+	for _, name := range c.Disable {
+		actions, labels := len(c.Actions), len(c.Labels)
+
+		c.Actions = slices.DeleteFunc(c.Actions, func(action Action) bool { return action.Name == name })
+		c.Labels = slices.DeleteFunc(c.Labels, func(label *Label) bool { return label.Name == name })
+
+		if actions == len(c.Actions) && labels == len(c.Labels) {
+			slogctx.Warn(ctx, fmt.Sprintf("'disable' entry %q did not match any action or label", name))
+		}
+	}
 }
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+// IncludedFile is a configuration file read from an include source
+type IncludedFile struct {
+	// ID uniquely identifies the file across all include sources, and is used for cycle detection
+	ID string
+
+	// Content is the raw YAML configuration
+	Content string
+}
+
+// IncludeResolver reads the configuration files of one kind of include source
+type IncludeResolver interface {
+	// Resolve returns the files of the include, in the order they were listed
+	Resolve(ctx context.Context, include Include) ([]IncludedFile, error)
+}
+
+// IncludeResolvers maps the include types (e.g. [IncludeTypeProject]) to their resolver
+type IncludeResolvers map[string]IncludeResolver
+
+// NewIncludeResolvers returns the resolvers for every include type.
+//
+// The 'local' includes are read from the repository of the Merge Request, at the same ref as the configuration file
+func NewIncludeResolvers(client scm.Client, ref string) IncludeResolvers {
+	return IncludeResolvers{
+		IncludeTypeProject: &ProjectIncludeResolver{Client: client},
+		IncludeTypeLocal:   &LocalIncludeResolver{Client: client, Ref: ref},
+		IncludeTypeURL:     &URLIncludeResolver{HTTPClient: &http.Client{Timeout: 15 * time.Second}},
+	}
+}
+
+// Resolve reads the files of the include with the resolver for its type
+func (r IncludeResolvers) Resolve(ctx context.Context, include Include) ([]IncludedFile, error) {
+	includeType, err := include.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, ok := r[includeType]
+	if !ok {
+		return nil, fmt.Errorf("'%s' includes are not supported here", includeType)
+	}
+
+	return resolver.Resolve(ctx, include)
+}
+
+// ProjectIncludeResolver reads files from another GitLab project or GitHub repository
+type ProjectIncludeResolver struct {
+	Client scm.Client
+}
+
+func (r *ProjectIncludeResolver) Resolve(ctx context.Context, include Include) ([]IncludedFile, error) {
+	files, err := r.Client.GetProjectFiles(ctx, include.Project, include.Ref, include.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load included config files from project [%s]: %w", include.Project, err)
+	}
+
+	ref := "HEAD"
+	if include.Ref != nil {
+		ref = *include.Ref
+	}
+
+	result := make([]IncludedFile, 0, len(include.Files))
+
+	for _, file := range include.Files {
+		content, ok := files[file]
+		if !ok {
+			return nil, fmt.Errorf("configuration file [%s] in project [%s] does not exist (or could not be read)", file, include.Project)
+		}
+
+		result = append(result, IncludedFile{ID: fmt.Sprintf("%s@%s:%s", include.Project, ref, file), Content: content})
+	}
+
+	return result, nil
+}
+
+// LocalIncludeResolver reads files from the repository of the Merge Request
+type LocalIncludeResolver struct {
+	Client scm.Client
+	Ref    string
+}
+
+func (r *LocalIncludeResolver) Resolve(ctx context.Context, include Include) ([]IncludedFile, error) {
+	result := make([]IncludedFile, 0, len(include.Local))
+
+	for _, file := range include.Local {
+		reader, err := r.Client.MergeRequests().GetRemoteConfig(ctx, file, r.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load local config file [%s]: %w", file, err)
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read local config file [%s]: %w", file, err)
+		}
+
+		result = append(result, IncludedFile{ID: "local:" + file, Content: string(content)})
+	}
+
+	return result, nil
+}
+
+// maxURLIncludeSize is the largest configuration file that will be read from a URL
+const maxURLIncludeSize = 1 << 20
+
+// URLIncludeResolver reads a file over HTTPS, optionally verifying its SHA256 checksum
+type URLIncludeResolver struct {
+	HTTPClient *http.Client
+}
+
+func (r *URLIncludeResolver) Resolve(ctx context.Context, include Include) ([]IncludedFile, error) {
+	parsed, err := url.Parse(include.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include url [%s]: %w", include.URL, err)
+	}
+
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("include url [%s] must use https", include.URL)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, include.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load included config file [%s]: %w", include.URL, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to load included config file [%s]: status code %d", include.URL, response.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxURLIncludeSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read included config file [%s]: %w", include.URL, err)
+	}
+
+	if len(content) > maxURLIncludeSize {
+		return nil, fmt.Errorf("included config file [%s] is larger than %d bytes", include.URL, maxURLIncludeSize)
+	}
+
+	if len(include.SHA256) > 0 {
+		checksum := sha256.Sum256(content)
+
+		if actual := hex.EncodeToString(checksum[:]); !strings.EqualFold(actual, include.SHA256) {
+			return nil, fmt.Errorf("included config file [%s] has sha256 %s, expected %s", include.URL, actual, include.SHA256)
+		}
+	}
+
+	return []IncludedFile{{ID: include.URL, Content: string(content)}}, nil
+}
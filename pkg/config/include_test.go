@@ -0,0 +1,310 @@
+package config_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/stretchr/testify/require"
+)
+
+// includeClient serves included files from memory, keyed by "project:file", files
+// in the repository of the Merge Request are keyed by "local@ref:file"
+type includeClient struct {
+	scm.Client
+
+	files map[string]string
+}
+
+func (c *includeClient) MergeRequests() scm.MergeRequestClient {
+	return &includeMergeRequestClient{files: c.files}
+}
+
+type includeMergeRequestClient struct {
+	scm.MergeRequestClient
+
+	files map[string]string
+}
+
+func (c *includeMergeRequestClient) GetRemoteConfig(_ context.Context, name, ref string) (io.Reader, error) {
+	content, ok := c.files["local@"+ref+":"+name]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", name)
+	}
+
+	return strings.NewReader(content), nil
+}
+
+func (c *includeClient) GetProjectFiles(_ context.Context, project string, _ *string, files []string) (map[string]string, error) {
+	result := map[string]string{}
+
+	for _, file := range files {
+		content, ok := c.files[project+":"+file]
+		if !ok {
+			return nil, fmt.Errorf("file %s not found in %s", file, project)
+		}
+
+		result[file] = content
+	}
+
+	return result, nil
+}
+
+func actionNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Actions))
+	for _, action := range cfg.Actions {
+		names = append(names, action.Name)
+	}
+
+	return names
+}
+
+func TestConfig_LoadIncludes_nested(t *testing.T) {
+	t.Parallel()
+
+	client := &includeClient{files: map[string]string{
+		"shared/rules:base.yml": `
+include:
+  - project: shared/common
+    files: [common.yml]
+actions:
+  - name: close stale
+    if: "true"
+    then: [{action: close}]
+`,
+		"shared/common:common.yml": `
+definitions:
+  is_stale: "true"
+actions:
+  - name: close stale
+    if: "false"
+    then: [{action: comment, message: ignored}]
+  - name: nag
+    if: is_stale
+    then: [{action: comment, message: nag}]
+label:
+  - name: shared
+    script: "true"
+`,
+	}}
+
+	cfg, err := config.ParseFileString(`
+include:
+  - project: shared/rules
+    files: [base.yml]
+actions:
+  - name: nag
+    if: "false"
+    then: [{action: close}]
+`)
+	require.NoError(t, err)
+	require.NoError(t, cfg.LoadIncludes(t.Context(), config.NewIncludeResolvers(client, "main")))
+
+	// The including configuration wins over the included one, at every level
+	require.Equal(t, []string{"nag", "close stale"}, actionNames(cfg))
+	require.Equal(t, "false", cfg.Actions[0].If)
+	require.Equal(t, "true", cfg.Actions[1].If)
+
+	require.Len(t, cfg.Labels, 1)
+	require.Equal(t, config.Definitions{"is_stale": "true"}, cfg.Definitions)
+}
+
+func TestConfig_LoadIncludes_cycle(t *testing.T) {
+	t.Parallel()
+
+	client := &includeClient{files: map[string]string{
+		"a:a.yml": "include: [{project: b, files: [b.yml]}]",
+		"b:b.yml": "include: [{project: a, files: [a.yml]}]",
+	}}
+
+	cfg, err := config.ParseFileString("include: [{project: a, files: [a.yml]}]")
+	require.NoError(t, err)
+	require.EqualError(t, cfg.LoadIncludes(t.Context(), config.NewIncludeResolvers(client, "main")), "include cycle detected: a@HEAD:a.yml -> b@HEAD:b.yml -> a@HEAD:a.yml")
+}
+
+func TestConfig_LoadIncludes_depthLimit(t *testing.T) {
+	t.Parallel()
+
+	client := &includeClient{files: map[string]string{}}
+
+	for i := range config.MaxIncludeDepth + 1 {
+		client.files[fmt.Sprintf("p:%d.yml", i)] = fmt.Sprintf("include: [{project: p, files: [%d.yml]}]", i+1)
+	}
+
+	cfg, err := config.ParseFileString("include: [{project: p, files: [0.yml]}]")
+	require.NoError(t, err)
+	require.ErrorContains(t, cfg.LoadIncludes(t.Context(), config.NewIncludeResolvers(client, "main")), fmt.Sprintf("includes may not be nested more than %d levels deep", config.MaxIncludeDepth))
+}
+
+func TestConfig_LoadIncludes_disable(t *testing.T) {
+	t.Parallel()
+
+	client := &includeClient{files: map[string]string{
+		"shared:rules.yml": `
+actions:
+  - name: close stale
+    if: "true"
+    then: [{action: close}]
+  - name: nag
+    if: "true"
+    then: [{action: comment, message: nag}]
+label:
+  - name: stale
+    script: "true"
+  - name: bug
+    script: "true"
+`,
+	}}
+
+	cfg, err := config.ParseFileString(`
+include:
+  - project: shared
+    files: [rules.yml]
+disable:
+  - close stale
+  - bug
+`)
+	require.NoError(t, err)
+	require.NoError(t, cfg.LoadIncludes(t.Context(), config.NewIncludeResolvers(client, "main")))
+
+	require.Equal(t, []string{"nag"}, actionNames(cfg))
+	require.Len(t, cfg.Labels, 1)
+	require.Equal(t, "stale", cfg.Labels[0].Name)
+}
+
+func TestConfig_LoadIncludes_local(t *testing.T) {
+	t.Parallel()
+
+	client := &includeClient{files: map[string]string{
+		"local@main:.scm-engine/labels.yml": `
+include:
+  - project: shared/rules
+    ref: v1
+    files: [rules.yml]
+label:
+  - name: local
+    script: "true"
+`,
+		// 'local' includes in a project file are read from that project, at the same ref
+		"shared/rules:rules.yml": "include: [{local: [nested.yml]}]",
+		"shared/rules:nested.yml": `
+label:
+  - name: nested
+    script: "true"
+`,
+	}}
+
+	cfg, err := config.ParseFileString("include: [{local: [.scm-engine/labels.yml]}]")
+	require.NoError(t, err)
+	require.NoError(t, cfg.LoadIncludes(t.Context(), config.NewIncludeResolvers(client, "main")))
+
+	require.Len(t, cfg.Labels, 2)
+	require.Equal(t, "local", cfg.Labels[0].Name)
+	require.Equal(t, "nested", cfg.Labels[1].Name)
+}
+
+func TestConfig_LoadIncludes_url(t *testing.T) {
+	t.Parallel()
+
+	const content = `
+label:
+  - name: remote
+    script: "true"
+`
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rules.yml":
+			w.Write([]byte(content))
+
+		case "/local.yml":
+			w.Write([]byte("include: [{local: [other.yml]}]"))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	checksum := sha256.Sum256([]byte(content))
+
+	resolvers := config.IncludeResolvers{
+		config.IncludeTypeURL: &config.URLIncludeResolver{HTTPClient: server.Client()},
+	}
+
+	tests := []struct {
+		name    string
+		include config.Include
+		wantErr string
+	}{
+		{
+			name:    "without checksum",
+			include: config.Include{URL: server.URL + "/rules.yml"},
+		},
+		{
+			name:    "matching checksum",
+			include: config.Include{URL: server.URL + "/rules.yml", SHA256: hex.EncodeToString(checksum[:])},
+		},
+		{
+			name:    "mismatching checksum",
+			include: config.Include{URL: server.URL + "/rules.yml", SHA256: "abc"},
+			wantErr: fmt.Sprintf("included config file [%s/rules.yml] has sha256 %s, expected abc", server.URL, hex.EncodeToString(checksum[:])),
+		},
+		{
+			name:    "not found",
+			include: config.Include{URL: server.URL + "/missing.yml"},
+			wantErr: fmt.Sprintf("failed to load included config file [%s/missing.yml]: status code 404", server.URL),
+		},
+		{
+			name:    "plain http",
+			include: config.Include{URL: "http://example.com/rules.yml"},
+			wantErr: "include url [http://example.com/rules.yml] must use https",
+		},
+		{
+			name:    "local include within url include",
+			include: config.Include{URL: server.URL + "/local.yml"},
+			wantErr: fmt.Sprintf("failed to load remote config file [%[1]s/local.yml]: 'local' includes are not supported in files included from %[1]s/local.yml", server.URL),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{Includes: []config.Include{tt.include}}
+
+			err := cfg.LoadIncludes(t.Context(), resolvers)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, cfg.Labels, 1)
+			require.Equal(t, "remote", cfg.Labels[0].Name)
+		})
+	}
+}
+
+func TestInclude_Type(t *testing.T) {
+	t.Parallel()
+
+	includeType, err := config.Include{Local: []string{"a.yml"}}.Type()
+	require.NoError(t, err)
+	require.Equal(t, config.IncludeTypeLocal, includeType)
+
+	_, err = config.Include{}.Type()
+	require.EqualError(t, err, "include must have one of 'project', 'local' or 'url'")
+
+	_, err = config.Include{Project: "a", URL: "https://example.com/a.yml"}.Type()
+	require.EqualError(t, err, "include may only have one of 'project', 'local' or 'url', got project, url")
+}
@@ -6,12 +6,13 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/expr-lang/expr"
-	"github.com/expr-lang/expr/patcher"
 	"github.com/expr-lang/expr/vm"
 	"github.com/jippi/scm-engine/pkg/scm"
-	"github.com/jippi/scm-engine/pkg/stdlib"
 	"github.com/jippi/scm-engine/pkg/tui"
 	"github.com/jippi/scm-engine/pkg/types"
 	slogctx "github.com/veqryn/slog-context"
@@ -28,7 +29,11 @@ const (
 type Labels []*Label
 
 func (labels Labels) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]scm.EvaluationResult, error) {
-	var results []scm.EvaluationResult
+	var (
+		results []scm.EvaluationResult
+		stale   []scm.EvaluationResult
+		winners = map[string]string{}
+	)
 
 	// Evaluate labels
 	for _, label := range labels {
@@ -49,7 +54,28 @@ func (labels Labels) Evaluate(ctx context.Context, evalContext scm.EvalContext)
 
 		slogctx.Debug(ctx, "Label evaluation done", slog.Any("label_eval_result", evaluationResult))
 
-		results = append(results, evaluationResult...)
+		for _, result := range evaluationResult {
+			// The 'generate' strategy only emits unmatched results for stale owned labels, they are
+			// added last so another label emitting the same name takes precedence
+			if label.Strategy == GenerateLabels && !result.Matched {
+				stale = append(stale, result)
+
+				continue
+			}
+
+			// Only the first matching label within an exclusive group is kept, the siblings are removed
+			if group := label.ExclusiveGroupFor(ctx, result.Name); len(group) > 0 && result.Matched {
+				if winner, ok := winners[group]; ok {
+					slogctx.Debug(ctx, "Label lost to another label in the same exclusive group", slog.String("label", result.Name), slog.String("exclusive_group", group), slog.String("winner", winner))
+
+					result.Matched = false
+				} else {
+					winners[group] = result.Name
+				}
+			}
+
+			results = append(results, result)
+		}
 	}
 
 	// Sanity/validation checks
@@ -69,6 +95,16 @@ func (labels Labels) Evaluate(ctx context.Context, evalContext scm.EvalContext)
 		seen[result.Name] = true
 	}
 
+	for _, result := range stale {
+		if seen[result.Name] {
+			continue
+		}
+
+		seen[result.Name] = true
+
+		results = append(results, result)
+	}
+
 	return results, nil
 }
 
@@ -119,6 +155,30 @@ type Label struct {
 	// See: https://jippi.github.io/scm-engine/configuration/#label.skip_if
 	SkipIf string `json:"skip_if,omitempty" yaml:"skip_if,omitempty"`
 
+	// (Optional) Existing labels on the MR starting with this prefix are owned by this label,
+	// and removed if the script did not generate them.
+	//
+	// May only be used with [generate] labelling type
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#label.owned_prefix
+	OwnedPrefix string `json:"owned_prefix,omitempty" yaml:"owned_prefix,omitempty"`
+
+	// (Optional) Existing labels on the MR matching this regular expression are owned by this label,
+	// and removed if the script did not generate them.
+	//
+	// May only be used with [generate] labelling type
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#label.owned_regex
+	OwnedRegex string `json:"owned_regex,omitempty" yaml:"owned_regex,omitempty"`
+
+	// (Optional) Only the first matching label within an exclusive group is added to the MR,
+	// the other labels in the group are removed.
+	//
+	// GitLab scoped labels (e.g. "size::S") are automatically grouped by their scope ("size")
+	//
+	// See: https://jippi.github.io/scm-engine/configuration/#label.exclusive_group
+	ExclusiveGroup string `json:"exclusive_group,omitempty" yaml:"exclusive_group,omitempty"`
+
 	//
 	// -- Internal state
 	//
@@ -129,10 +189,13 @@ type Label struct {
 	// skipIfCompiled is the [expr-lang](https://expr-lang.org/) [SkipIf] script pre-compiled
 	skipIfCompiled *vm.Program `json:"-" yaml:"-"`
 
+	// ownedRegexCompiled is the [OwnedRegex] pre-compiled
+	ownedRegexCompiled *regexp.Regexp `json:"-" yaml:"-"`
+
 	expectedReturnType any `json:"-" yaml:"-"`
 }
 
-func (p *Label) Setup(evalContext scm.EvalContext) error {
+func (p *Label) Setup(ctx context.Context, evalContext scm.EvalContext) error {
 	var scriptReturnType expr.Option
 
 	if len(p.Script) == 0 {
@@ -160,6 +223,10 @@ func (p *Label) Setup(evalContext scm.EvalContext) error {
 			return fmt.Errorf("[name] is required when using [type: %q]", ConditionalLabel)
 		}
 
+		if len(p.OwnedPrefix) > 0 || len(p.OwnedRegex) > 0 {
+			return fmt.Errorf("[owned_prefix] and [owned_regex] may only be specified when using [type: %q]", GenerateLabels)
+		}
+
 		p.expectedReturnType = true
 		scriptReturnType = expr.AsBool()
 
@@ -170,14 +237,14 @@ func (p *Label) Setup(evalContext scm.EvalContext) error {
 	var err error
 
 	if p.scriptCompiled == nil {
-		p.Color = tui.Replace(p.Color)
+		options, err := compileOptions(ctx, evalContext, scriptReturnType)
+		if err != nil {
+			return err
+		}
 
-		opts := make([]expr.Option, 0, len(stdlib.Functions)+4)
-		opts = append(opts, scriptReturnType, expr.Env(evalContext), stdlib.FunctionRenamer)
-		opts = append(opts, stdlib.Functions...)
-		opts = append(opts, expr.Patch(patcher.WithContext{Name: "ctx"}))
+		p.Color = tui.Replace(p.Color)
 
-		p.scriptCompiled, err = expr.Compile(p.Script, opts...)
+		p.scriptCompiled, err = expr.Compile(p.Script, options...)
 		if err != nil {
 			return fmt.Errorf("could not compile 'script' into valid expr-lang syntax: %w", err)
 		}
@@ -186,22 +253,38 @@ func (p *Label) Setup(evalContext scm.EvalContext) error {
 	if p.skipIfCompiled == nil && len(p.SkipIf) > 0 {
 		p.Color = tui.Replace(p.Color)
 
-		opts := make([]expr.Option, 0, len(stdlib.Functions)+4)
-		opts = append(opts, expr.AsBool(), expr.Env(evalContext), stdlib.FunctionRenamer)
-		opts = append(opts, stdlib.Functions...)
-		opts = append(opts, expr.Patch(patcher.WithContext{Name: "ctx"}))
+		options, err := compileOptions(ctx, evalContext, expr.AsBool())
+		if err != nil {
+			return err
+		}
 
-		p.skipIfCompiled, err = expr.Compile(p.SkipIf, opts...)
+		p.skipIfCompiled, err = expr.Compile(p.SkipIf, options...)
 		if err != nil {
 			return fmt.Errorf("could not compile 'if' into valid expr-lang syntax: %w", err)
 		}
 	}
 
+	if p.ownedRegexCompiled == nil && len(p.OwnedRegex) > 0 {
+		p.ownedRegexCompiled, err = regexp.Compile(p.OwnedRegex)
+		if err != nil {
+			return fmt.Errorf("could not compile 'owned_regex' into a valid regular expression: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// Owns returns true if the label name is owned by the label, see [OwnedPrefix] and [OwnedRegex]
+func (p *Label) Owns(name string) bool {
+	if len(p.OwnedPrefix) > 0 && strings.HasPrefix(name, p.OwnedPrefix) {
+		return true
+	}
+
+	return p.ownedRegexCompiled != nil && p.ownedRegexCompiled.MatchString(name)
+}
+
 func (p *Label) ShouldSkip(ctx context.Context, evalContext scm.EvalContext) (bool, error) {
-	if err := p.Setup(evalContext); err != nil {
+	if err := p.Setup(ctx, evalContext); err != nil {
 		return true, err
 	}
 
@@ -209,7 +292,7 @@ func (p *Label) ShouldSkip(ctx context.Context, evalContext scm.EvalContext) (bo
 }
 
 func (p *Label) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]scm.EvaluationResult, error) {
-	if err := p.Setup(evalContext); err != nil {
+	if err := p.Setup(ctx, evalContext); err != nil {
 		return nil, fmt.Errorf("failed to initialize expr script engine: %w", err)
 	}
 
@@ -264,9 +347,34 @@ func (p *Label) Evaluate(ctx context.Context, evalContext scm.EvalContext) ([]sc
 		return nil, fmt.Errorf("rule evaluation returned %T (%+v); must return %T", output, output, p.expectedReturnType)
 	}
 
+	if p.Strategy == GenerateLabels {
+		result = append(result, p.staleOwnedLabels(evalContext.GetLabels(), result)...)
+	}
+
 	return result, nil
 }
 
+// staleOwnedLabels returns an unmatched result for every existing label owned by the label that was not generated,
+// so generated label sets track the current state of the MR
+func (p *Label) staleOwnedLabels(existing []string, generated []scm.EvaluationResult) []scm.EvaluationResult {
+	var result []scm.EvaluationResult
+
+	for _, name := range existing {
+		if !p.Owns(name) {
+			continue
+		}
+
+		if slices.ContainsFunc(generated, func(r scm.EvaluationResult) bool { return r.Name == name }) {
+			continue
+		}
+
+		// The owned label may be generated by another label, with another color and description
+		result = append(result, scm.EvaluationResult{Name: name, Stale: true})
+	}
+
+	return result
+}
+
 func (p Label) resultForLabel(name string, matched bool) scm.EvaluationResult {
 	return scm.EvaluationResult{
 		Name:        name,
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/state"
+)
+
+// scopedLabelSeparator separates the scope from the value in GitLab scoped labels, e.g. "size::S"
+//
+// See: https://docs.gitlab.com/ee/user/project/labels.html#scoped-labels
+const scopedLabelSeparator = "::"
+
+// ExclusiveGroupFor returns the exclusive group of a label name emitted by the label.
+//
+// An explicit [ExclusiveGroup] wins, otherwise the scope of GitLab scoped labels is used on GitLab,
+// '::' has no special meaning on GitHub. An empty string means the label isn't part of any group
+func (p *Label) ExclusiveGroupFor(ctx context.Context, name string) string {
+	if len(p.ExclusiveGroup) > 0 {
+		return p.ExclusiveGroup
+	}
+
+	if state.Provider(ctx) != "gitlab" {
+		return ""
+	}
+
+	if idx := strings.LastIndex(name, scopedLabelSeparator); idx > 0 {
+		return name[:idx]
+	}
+
+	return ""
+}
+
+// ExclusiveGroupsWithoutFallback returns the exclusive groups where no label always matches,
+// meaning the MR may end up with no label from the group at all.
+//
+// A fallback is a 'conditional' label with the script 'true', usually the last one in the group
+func (labels Labels) ExclusiveGroupsWithoutFallback(ctx context.Context) []string {
+	var (
+		groups   []string
+		fallback = map[string]bool{}
+	)
+
+	for _, label := range labels {
+		var group string
+
+		switch label.Strategy {
+		case GenerateLabels:
+			// The names are only known at evaluation time, so only explicit groups can be checked
+			group = label.ExclusiveGroup
+
+		default:
+			group = label.ExclusiveGroupFor(ctx, label.Name)
+		}
+
+		if len(group) == 0 {
+			continue
+		}
+
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+
+		if label.Strategy != GenerateLabels && strings.TrimSpace(label.Script) == "true" && len(label.SkipIf) == 0 {
+			fallback[group] = true
+		}
+	}
+
+	return slices.DeleteFunc(groups, func(group string) bool {
+		return fallback[group]
+	})
+}
@@ -0,0 +1,108 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabel_ExclusiveGroupFor(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithProvider(t.Context(), "gitlab")
+
+	require.Empty(t, (&config.Label{}).ExclusiveGroupFor(ctx, "bug"))
+	require.Equal(t, "size", (&config.Label{}).ExclusiveGroupFor(ctx, "size::S"))
+	require.Equal(t, "team::backend", (&config.Label{}).ExclusiveGroupFor(ctx, "team::backend::lead"), "the scope is everything before the last '::'")
+	require.Equal(t, "status", (&config.Label{ExclusiveGroup: "status"}).ExclusiveGroupFor(ctx, "needs-review"))
+	require.Equal(t, "status", (&config.Label{ExclusiveGroup: "status"}).ExclusiveGroupFor(ctx, "size::S"), "an explicit group wins over the scope")
+}
+
+// GitHub has no scoped labels, so only explicit groups are used
+func TestLabel_ExclusiveGroupFor_github(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithProvider(t.Context(), "github")
+
+	require.Empty(t, (&config.Label{}).ExclusiveGroupFor(ctx, "team::a"))
+	require.Equal(t, "status", (&config.Label{ExclusiveGroup: "status"}).ExclusiveGroupFor(ctx, "team::a"))
+}
+
+// Only the first matching label in a group is kept, the rest are removed in the same update
+func TestLabels_Evaluate_exclusiveGroup(t *testing.T) {
+	t.Parallel()
+
+	labels := config.Labels{
+		{Name: "size::S", Script: `false`},
+		{Name: "size::M", Script: `true`},
+		{Name: "size::L", Script: `true`},
+		{Name: "blocked", Script: `true`, ExclusiveGroup: "status"},
+		{Name: "ready", Script: `true`, ExclusiveGroup: "status"},
+		{Name: "bug", Script: `true`},
+	}
+
+	results, err := labels.Evaluate(state.WithProvider(t.Context(), "gitlab"), evalContext("size::L", "ready"))
+	require.NoError(t, err)
+
+	got := map[string]bool{}
+	for _, result := range results {
+		got[result.Name] = result.Matched
+	}
+
+	require.Equal(t, map[string]bool{
+		"size::S": false,
+		"size::M": true,
+		"size::L": false,
+		"blocked": true,
+		"ready":   false,
+		"bug":     true,
+	}, got)
+}
+
+func TestLabels_ExclusiveGroupsWithoutFallback(t *testing.T) {
+	t.Parallel()
+
+	labels := config.Labels{
+		{Name: "size::S", Script: `merge_request.diff_stats.total < 10`},
+		{Name: "size::L", Script: ` true `},
+		{Name: "status::blocked", Script: `merge_request.has_label("blocked")`},
+		{Name: "status::ready", Script: `true`, SkipIf: `merge_request.draft`},
+		{Script: `["a", "b"]`, Strategy: config.GenerateLabels, ExclusiveGroup: "generated"},
+		{Script: `["scope::a"]`, Strategy: config.GenerateLabels},
+		{Name: "bug", Script: `false`},
+	}
+
+	require.Equal(t, []string{"status", "generated"}, labels.ExclusiveGroupsWithoutFallback(state.WithProvider(t.Context(), "gitlab")))
+
+	// '::' has no special meaning on GitHub
+	require.Equal(t, []string{"generated"}, labels.ExclusiveGroupsWithoutFallback(state.WithProvider(t.Context(), "github")))
+}
+
+// Labels with '::' in the name are independent on GitHub
+func TestLabels_Evaluate_exclusiveGroupGitHub(t *testing.T) {
+	t.Parallel()
+
+	labels := config.Labels{
+		{Name: "team::a", Script: `true`},
+		{Name: "team::b", Script: `true`},
+		{Name: "blocked", Script: `true`, ExclusiveGroup: "status"},
+		{Name: "ready", Script: `true`, ExclusiveGroup: "status"},
+	}
+
+	results, err := labels.Evaluate(state.WithProvider(t.Context(), "github"), evalContext())
+	require.NoError(t, err)
+
+	got := map[string]bool{}
+	for _, result := range results {
+		got[result.Name] = result.Matched
+	}
+
+	require.Equal(t, map[string]bool{
+		"team::a": true,
+		"team::b": true,
+		"blocked": true,
+		"ready":   false,
+	}, got)
+}
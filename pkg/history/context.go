@@ -0,0 +1,36 @@
+package history
+
+import (
+	"context"
+)
+
+type contextKey uint
+
+const (
+	entryKey contextKey = iota
+	logKey
+)
+
+// WithEntry attaches the entry being recorded for the current evaluation
+func WithEntry(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryKey, entry)
+}
+
+func EntryFromContext(ctx context.Context) *Entry {
+	// Recording history is optional, so it's not an error for it to be missing
+	entry, _ := ctx.Value(entryKey).(*Entry)
+
+	return entry
+}
+
+// WithLog configures where evaluations are recorded
+func WithLog(ctx context.Context, log *Log) context.Context {
+	return context.WithValue(ctx, logKey, log)
+}
+
+func LogFromContext(ctx context.Context) *Log {
+	// Recording history is optional, so it's not an error for it to be missing
+	log, _ := ctx.Value(logKey).(*Log)
+
+	return log
+}
@@ -0,0 +1,182 @@
+// Package history keeps an append-only log of every Merge Request evaluation,
+// so it's possible to answer "why did scm-engine do that?" long after the fact.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is the outcome of a single Merge Request evaluation
+type Entry struct {
+	EvaluationID  string        `json:"evaluation_id"`
+	Time          time.Time     `json:"time"`
+	Duration      time.Duration `json:"duration"`
+	Provider      string        `json:"provider"`
+	Project       string        `json:"project"`
+	MergeRequest  string        `json:"merge_request"`
+	CommitSHA     string        `json:"commit_sha,omitempty"`
+	DryRun        bool          `json:"dry_run"`
+	LabelsAdded   []string      `json:"labels_added,omitempty"`
+	LabelsRemoved []string      `json:"labels_removed,omitempty"`
+	Actions       []Action      `json:"actions,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// Action is an action that was applied during the evaluation
+type Action struct {
+	Name string `json:"name"`
+	// Branch is "else" when the 'else' steps were applied, and empty for 'then'
+	Branch string `json:"branch,omitempty"`
+	Steps  []Step `json:"steps"`
+}
+
+// Possible [Step] statuses
+const (
+	StepSucceeded = "succeeded"
+	StepFailed    = "failed"
+	StepSkipped   = "skipped"
+)
+
+// Step is the outcome of a single step within an action
+type Step struct {
+	Action string `json:"action"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Succeeded returns the steps that were applied without errors
+func (a Action) Succeeded() []string {
+	var result []string
+
+	for _, step := range a.Steps {
+		if step.Status == StepSucceeded {
+			result = append(result, step.Action)
+		}
+	}
+
+	return result
+}
+
+// AddAction records that an action was applied, it's safe to call on a nil Entry
+func (e *Entry) AddAction(action Action) {
+	if e == nil {
+		return
+	}
+
+	e.Actions = append(e.Actions, action)
+}
+
+// Filter narrows down the entries returned by [Log.Query], zero values match everything
+type Filter struct {
+	Project      string
+	MergeRequest string
+	Since        time.Time
+	Until        time.Time
+	// Limit returns only the most recent entries
+	Limit int
+}
+
+func (f Filter) matches(entry Entry) bool {
+	switch {
+	case len(f.Project) > 0 && entry.Project != f.Project:
+		return false
+
+	case len(f.MergeRequest) > 0 && entry.MergeRequest != f.MergeRequest:
+		return false
+
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+
+	case !f.Until.IsZero() && entry.Time.After(f.Until):
+		return false
+	}
+
+	return true
+}
+
+// Log is an append-only JSON Lines file with one [Entry] per line
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append writes the entry at the end of the log, creating the file if needed
+func (l *Log) Append(entry Entry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not open history file: %w", err)
+	}
+
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		file.Close()
+
+		return fmt.Errorf("could not write history file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// Query returns the entries matching the filter, oldest first
+func (l *Log) Query(filter Filter) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 10*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("could not read history file line %d: %w", line, err)
+		}
+
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read history file: %w", err)
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/history"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	log := history.NewLog(filepath.Join(t.TempDir(), "history.jsonl"))
+
+	// Querying before anything was recorded is not an error
+	entries, err := log.Query(history.Filter{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	recorded := []history.Entry{
+		{EvaluationID: "a", Time: start, Project: "jippi/scm-engine", MergeRequest: "1", LabelsAdded: []string{"bug"}},
+		{EvaluationID: "b", Time: start.Add(time.Hour), Project: "jippi/scm-engine", MergeRequest: "2", Error: "boom"},
+		{EvaluationID: "c", Time: start.Add(2 * time.Hour), Project: "jippi/other", MergeRequest: "1"},
+		{EvaluationID: "d", Time: start.Add(3 * time.Hour), Project: "jippi/scm-engine", MergeRequest: "1", Actions: []history.Action{{Name: "close stale", Steps: []history.Step{{Action: "comment", Status: history.StepSucceeded}}}}},
+	}
+
+	for _, entry := range recorded {
+		require.NoError(t, log.Append(entry))
+	}
+
+	tests := []struct {
+		name   string
+		filter history.Filter
+		want   []string
+	}{
+		{name: "everything, oldest first", filter: history.Filter{}, want: []string{"a", "b", "c", "d"}},
+		{name: "project", filter: history.Filter{Project: "jippi/scm-engine"}, want: []string{"a", "b", "d"}},
+		{name: "project and merge request", filter: history.Filter{Project: "jippi/scm-engine", MergeRequest: "1"}, want: []string{"a", "d"}},
+		{name: "since", filter: history.Filter{Since: start.Add(90 * time.Minute)}, want: []string{"c", "d"}},
+		{name: "until", filter: history.Filter{Until: start.Add(time.Hour)}, want: []string{"a", "b"}},
+		{name: "limit keeps the most recent", filter: history.Filter{Limit: 2}, want: []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := log.Query(tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				ids = append(ids, entry.EvaluationID)
+			}
+
+			require.Equal(t, tt.want, ids)
+		})
+	}
+
+	entries, err = log.Query(history.Filter{MergeRequest: "1", Project: "jippi/scm-engine"})
+	require.NoError(t, err)
+	require.Equal(t, recorded[3], entries[1], "entries must survive the round trip")
+}
+
+func TestLog_rejectsCorruptLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"evaluation_id\":\"a\"}\n\nnot json\n"), 0o600))
+
+	_, err := history.NewLog(path).Query(history.Filter{})
+	require.ErrorContains(t, err, "could not read history file line 3")
+}
+
+func TestEntry_AddAction(t *testing.T) {
+	t.Parallel()
+
+	action := history.Action{Name: "close stale", Steps: []history.Step{
+		{Action: "comment", Status: history.StepFailed, Error: "boom"},
+		{Action: "add_label", Status: history.StepSucceeded},
+		{Action: "close", Status: history.StepSkipped},
+	}}
+
+	entry := &history.Entry{}
+	entry.AddAction(action)
+
+	require.Equal(t, []history.Action{action}, entry.Actions)
+	require.Equal(t, []string{"add_label"}, entry.Actions[0].Succeeded())
+
+	// Recording is optional, so a missing entry must not panic
+	history.EntryFromContext(t.Context()).AddAction(history.Action{Name: "ignored"})
+}
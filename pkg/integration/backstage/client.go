@@ -134,10 +134,25 @@ func (c *Client) ListGroupMembers(ctx context.Context, groupEntityRef *EntityRef
 
 func (c *Client) GetOwnersForGitLabProject(ctx context.Context, projectName string) ([]scm.Actor, error) {
 	// (kind=system AND metadata.name=?) OR (...)
-	entityRef, err := c.GetEntityOwner(ctx,
+	return c.getOwners(ctx, convertBackstageEntitiesToGitLabActors,
 		"kind=system,metadata.name="+projectName,
 		"kind=system,metadata.annotations.gitlab.com/project="+projectName,
 	)
+}
+
+// GetOwnersForGitHubProject returns the owners of the system matching the GitHub "owner/repo" project slug
+func (c *Client) GetOwnersForGitHubProject(ctx context.Context, projectSlug string) ([]scm.Actor, error) {
+	_, projectName, _ := strings.Cut(projectSlug, "/")
+
+	// (kind=system AND metadata.name=?) OR (...)
+	return c.getOwners(ctx, convertBackstageEntitiesToGitHubActors,
+		"kind=system,metadata.name="+projectName,
+		"kind=system,metadata.annotations.github.com/project-slug="+projectSlug,
+	)
+}
+
+func (c *Client) getOwners(ctx context.Context, convert func(...go_backstage.Entity) []scm.Actor, filters ...string) ([]scm.Actor, error) {
+	entityRef, err := c.GetEntityOwner(ctx, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -150,14 +165,14 @@ func (c *Client) GetOwnersForGitLabProject(ctx context.Context, projectName stri
 			return nil, err
 		}
 
-		actors = convertBackstageEntitiesToGitLabActors(userEntity.Entity)
+		actors = convert(userEntity.Entity)
 	} else if entityRef.IsGroup() {
 		entities, err := c.ListGroupMembers(ctx, entityRef)
 		if err != nil {
 			return nil, err
 		}
 
-		actors = convertBackstageEntitiesToGitLabActors(entities...)
+		actors = convert(entities...)
 	}
 
 	return actors, nil
@@ -249,3 +264,21 @@ func convertBackstageEntitiesToGitLabActors(entities ...go_backstage.Entity) []s
 
 	return actors
 }
+
+// Helper function to convert Backstage user entities to GitHub actors
+func convertBackstageEntitiesToGitHubActors(entities ...go_backstage.Entity) []scm.Actor {
+	actors := make([]scm.Actor, 0, len(entities))
+
+	for _, entity := range entities {
+		login, ok := entity.Metadata.Annotations["github.com/user-login"]
+		if !ok {
+			continue
+		}
+
+		actors = append(actors, scm.Actor{
+			Username: login,
+		})
+	}
+
+	return actors
+}
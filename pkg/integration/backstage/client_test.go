@@ -240,3 +240,47 @@ func TestClient_GetOwnersForGitLabProject(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetOwnersForGitHubProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    []scm.Actor
+		wantErr error
+	}{
+		{
+			name: "found",
+			arg:  "test-org/test-system",
+			want: []scm.Actor{
+				{
+					Username: "test-user",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := testutils.GetRecorder(t)
+			defer r.Stop()
+
+			client, err := backstage.NewClient(t.Context(), "https://backstage.example.com", "", r.GetDefaultClient())
+			require.NoError(t, err)
+
+			owners, err := client.GetOwnersForGitHubProject(t.Context(), tt.arg)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.ErrorContains(t, tt.wantErr, err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.DeepEqual(t, tt.want, owners)
+		})
+	}
+}
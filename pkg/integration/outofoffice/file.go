@@ -0,0 +1,191 @@
+package outofoffice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"gopkg.in/yaml.v3"
+)
+
+// Absence is a period of time where a user is unavailable to review
+type Absence struct {
+	// Users identifies who is absent, by username, user ID or email
+	Users []string
+	// From is the start of the absence (inclusive)
+	From time.Time
+	// To is the end of the absence (exclusive)
+	To time.Time
+	// Reason is a human readable explanation, for example "vacation"
+	Reason string
+}
+
+// FileProvider reads absences from a YAML or iCal (.ics) file.
+//
+// The file is read again whenever it changes on disk, so a long-running server
+// picks up new absences without a restart
+type FileProvider struct {
+	path string
+
+	mu       sync.Mutex
+	modTime  time.Time
+	absences []Absence
+}
+
+var _ scm.OutOfOfficeProvider = (*FileProvider)(nil)
+
+// NewFileProvider creates a provider reading absences from path, the format is picked from the file extension
+func NewFileProvider(path string) (*FileProvider, error) {
+	provider := &FileProvider{path: path}
+
+	if err := provider.reload(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (p *FileProvider) OutOfOffice(ctx context.Context, actor scm.Actor, at time.Time) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.reload(); err != nil {
+		return "", err
+	}
+
+	for _, absence := range p.absences {
+		if at.Before(absence.From) || !at.Before(absence.To) {
+			continue
+		}
+
+		if !actor.IsAny(absence.Users...) && (actor.Email == nil || !containsFold(absence.Users, *actor.Email)) {
+			continue
+		}
+
+		if len(absence.Reason) == 0 {
+			return "out of office", nil
+		}
+
+		return absence.Reason, nil
+	}
+
+	return "", nil
+}
+
+// reload parses the file if it changed since it was last read
+func (p *FileProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("could not read out of office file: %w", err)
+	}
+
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("could not read out of office file: %w", err)
+	}
+
+	var absences []Absence
+
+	switch strings.ToLower(filepath.Ext(p.path)) {
+	case ".ics", ".ical":
+		absences, err = parseICal(string(content))
+
+	default:
+		absences, err = parseYAML(content)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not parse out of office file %q: %w", p.path, err)
+	}
+
+	p.absences = absences
+	p.modTime = info.ModTime()
+
+	return nil
+}
+
+type yamlFile struct {
+	Absences []struct {
+		User   string   `yaml:"user"`
+		Users  []string `yaml:"users"`
+		From   string   `yaml:"from"`
+		To     string   `yaml:"to"`
+		Reason string   `yaml:"reason"`
+	} `yaml:"absences"`
+}
+
+// parseYAML reads absences in the format:
+//
+//	absences:
+//	  - user: alice
+//	    from: 2026-10-01
+//	    to: 2026-10-14
+//	    reason: vacation
+//
+// Dates without a time cover the whole day, so "to" is inclusive
+func parseYAML(content []byte) ([]Absence, error) {
+	var file yamlFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	absences := make([]Absence, 0, len(file.Absences))
+
+	for i, entry := range file.Absences {
+		users := entry.Users
+		if len(entry.User) > 0 {
+			users = append(users, entry.User)
+		}
+
+		if len(users) == 0 {
+			return nil, fmt.Errorf("absence #%d has no 'user'", i+1)
+		}
+
+		from, _, err := parseYAMLTime(entry.From)
+		if err != nil {
+			return nil, fmt.Errorf("absence #%d has an invalid 'from': %w", i+1, err)
+		}
+
+		to, isDate, err := parseYAMLTime(entry.To)
+		if err != nil {
+			return nil, fmt.Errorf("absence #%d has an invalid 'to': %w", i+1, err)
+		}
+
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+
+		absences = append(absences, Absence{Users: users, From: from, To: to, Reason: entry.Reason})
+	}
+
+	return absences, nil
+}
+
+func parseYAMLTime(value string) (time.Time, bool, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+
+	datetime, err := time.Parse(time.RFC3339, value)
+
+	return datetime, false, err
+}
+
+func containsFold(values []string, needle string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, needle) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package outofoffice_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/integration/outofoffice"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/stretchr/testify/require"
+)
+
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+
+	return parsed
+}
+
+func TestFileProvider_OutOfOffice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		file  string
+		actor scm.Actor
+		at    string
+		want  string
+	}{
+		{name: "yaml: during a whole day absence", file: "absences.yml", actor: scm.Actor{Username: "alice"}, at: "2026-10-07T12:00:00Z", want: "vacation"},
+		{name: "yaml: 'to' date is inclusive", file: "absences.yml", actor: scm.Actor{Username: "alice"}, at: "2026-10-14T23:59:59Z", want: "vacation"},
+		{name: "yaml: after the absence", file: "absences.yml", actor: scm.Actor{Username: "alice"}, at: "2026-10-15T00:00:00Z", want: ""},
+		{name: "yaml: before the absence", file: "absences.yml", actor: scm.Actor{Username: "alice"}, at: "2026-09-30T23:59:59Z", want: ""},
+		{name: "yaml: absence with a time range", file: "absences.yml", actor: scm.Actor{Username: "bob"}, at: "2026-10-05T10:00:00Z", want: "dentist"},
+		{name: "yaml: outside the time range", file: "absences.yml", actor: scm.Actor{Username: "bob"}, at: "2026-10-05T13:00:00Z", want: ""},
+		{name: "yaml: matched by GitLab user ID", file: "absences.yml", actor: scm.Actor{ID: "gid://gitlab/User/42"}, at: "2026-10-05T10:00:00Z", want: "dentist"},
+		{name: "yaml: matched by email without a reason", file: "absences.yml", actor: scm.Actor{Username: "carol", Email: scm.Ptr("Carol@Example.com")}, at: "2026-10-01T08:00:00Z", want: "out of office"},
+		{name: "yaml: someone else", file: "absences.yml", actor: scm.Actor{Username: "dave"}, at: "2026-10-07T12:00:00Z", want: ""},
+		{name: "ical: all day event matched by CN", file: "absences.ics", actor: scm.Actor{Username: "alice"}, at: "2026-10-14T12:00:00Z", want: "Vacation, back on the 15th"},
+		{name: "ical: DTEND of all day events is exclusive", file: "absences.ics", actor: scm.Actor{Username: "alice"}, at: "2026-10-15T00:00:00Z", want: ""},
+		{name: "ical: folded attendee matched by email", file: "absences.ics", actor: scm.Actor{Email: scm.Ptr("bob@example.com")}, at: "2026-10-05T07:30:00Z", want: "Conference"},
+		{name: "ical: TZID is respected", file: "absences.ics", actor: scm.Actor{Username: "bob"}, at: "2026-10-05T11:30:00Z", want: ""},
+		{name: "ical: events without attendees are ignored", file: "absences.ics", actor: scm.Actor{Username: "dave"}, at: "2026-10-01T12:00:00Z", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := outofoffice.NewFileProvider(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+
+			got, err := provider.OutOfOffice(t.Context(), tt.actor, at(t, tt.at))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewFileProvider_invalidFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "missing file", file: "missing.yml", wantErr: "could not read out of office file"},
+		{name: "absence without user", file: "absences.yml", content: "absences:\n  - from: 2026-10-01\n    to: 2026-10-02\n", wantErr: "absence #1 has no 'user'"},
+		{name: "invalid date", file: "absences.yml", content: "absences:\n  - user: alice\n    from: tomorrow\n    to: 2026-10-02\n", wantErr: "absence #1 has an invalid 'from'"},
+		{name: "unterminated event", file: "absences.ics", content: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261001T000000Z\n", wantErr: "unterminated VEVENT"},
+		{name: "event without start", file: "absences.ics", content: "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n", wantErr: "line 3: event has no DTSTART"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.file)
+
+			if len(tt.content) > 0 {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+
+			_, err := outofoffice.NewFileProvider(path)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// A long-running server must pick up changes to the file without a restart
+func TestFileProvider_reloadsChangedFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "absences.yml")
+	require.NoError(t, os.WriteFile(path, []byte("absences: []\n"), 0o600))
+
+	provider, err := outofoffice.NewFileProvider(path)
+	require.NoError(t, err)
+
+	got, err := provider.OutOfOffice(t.Context(), scm.Actor{Username: "alice"}, at(t, "2026-10-01T12:00:00Z"))
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	require.NoError(t, os.WriteFile(path, []byte("absences:\n  - user: alice\n    from: 2026-10-01\n    to: 2026-10-01\n    reason: sick\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	got, err = provider.OutOfOffice(t.Context(), scm.Actor{Username: "alice"}, at(t, "2026-10-01T12:00:00Z"))
+	require.NoError(t, err)
+	require.Equal(t, "sick", got)
+}
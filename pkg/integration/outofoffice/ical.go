@@ -0,0 +1,150 @@
+package outofoffice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseICal reads the VEVENT entries of an iCal (RFC 5545) calendar, such as an
+// exported team absence calendar.
+//
+// The attendees (email and CN) identify who is absent and the SUMMARY is the reason.
+// Recurring events (RRULE) are not expanded, only their first occurrence is used
+func parseICal(content string) ([]Absence, error) {
+	var (
+		absences []Absence
+		current  *Absence
+		allDay   bool
+	)
+
+	for number, line := range unfoldICal(content) {
+		name, params, value := splitICalLine(line)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &Absence{}
+			allDay = false
+
+		case current == nil:
+			continue
+
+		case name == "END" && value == "VEVENT":
+			if current.From.IsZero() {
+				return nil, fmt.Errorf("line %d: event has no DTSTART", number+1)
+			}
+
+			if current.To.IsZero() {
+				// An all-day event without DTEND lasts one day, any other event is instant
+				current.To = current.From
+				if allDay {
+					current.To = current.From.AddDate(0, 0, 1)
+				}
+			}
+
+			if len(current.Users) > 0 {
+				absences = append(absences, *current)
+			}
+
+			current = nil
+
+		case name == "DTSTART":
+			from, isDate, err := parseICalTime(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTSTART: %w", number+1, err)
+			}
+
+			current.From = from
+			allDay = isDate
+
+		case name == "DTEND":
+			to, _, err := parseICalTime(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTEND: %w", number+1, err)
+			}
+
+			current.To = to
+
+		case name == "SUMMARY":
+			current.Reason = unescapeICal(value)
+
+		case name == "ATTENDEE":
+			current.Users = append(current.Users, strings.TrimPrefix(strings.ToLower(value), "mailto:"))
+
+			if cn, ok := params["CN"]; ok {
+				current.Users = append(current.Users, cn)
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, errors.New("unterminated VEVENT")
+	}
+
+	return absences, nil
+}
+
+// unfoldICal joins folded lines, a line starting with whitespace continues the previous one
+func unfoldICal(content string) []string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// splitICalLine splits "NAME;PARAM=value:VALUE" into its parts
+func splitICalLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseICalTime(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		date, err := time.Parse("20060102", value)
+
+		return date, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		datetime, err := time.Parse("20060102T150405Z", value)
+
+		return datetime, false, err
+	}
+
+	location := time.UTC
+
+	if tzid, ok := params["TZID"]; ok {
+		var err error
+
+		location, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+	datetime, err := time.ParseInLocation("20060102T150405", value, location)
+
+	return datetime, false, err
+}
+
+func unescapeICal(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,153 @@
+// Package ruletest runs a configuration against recorded evaluation contexts and compares
+// the outcome with the expected labels and actions, so rules can be tested in CI.
+package ruletest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a single rule test, read from a YAML file
+//
+//	name: stale merge requests are closed
+//	provider: gitlab
+//	context: stale-mr.json
+//	stored_values:
+//	  warned_at: "2026-10-01T00:00:00Z"
+//	expect:
+//	  labels:
+//	    - name: stale
+//	    - name: active
+//	      matched: false
+//	  actions:
+//	    - close stale
+type Fixture struct {
+	// Name of the test, defaults to the file name
+	Name string `yaml:"name"`
+	// Provider is either "gitlab" (default) or "github"
+	Provider string `yaml:"provider"`
+	// Context is the path to an evaluation context snapshot written by --dump-context, relative to the fixture file
+	Context string `yaml:"context"`
+	// StoredValues are stored on the Merge Request before evaluation, see the 'store_value' action
+	StoredValues map[string]any `yaml:"stored_values"`
+	// Expect is the expected outcome of the evaluation
+	Expect Expectation `yaml:"expect"`
+
+	// path to the fixture file
+	path string
+}
+
+// Expectation is the expected outcome of evaluating a fixture
+type Expectation struct {
+	// Labels are the expected label evaluation results.
+	//
+	// Only the fields set in the fixture are compared, and a matched label missing from the list is a failure
+	Labels []ExpectedLabel `yaml:"labels"`
+	// Actions are the names of the actions expected to be applied, in order
+	Actions []string `yaml:"actions"`
+}
+
+// ExpectedLabel is the expected evaluation result for a label
+type ExpectedLabel struct {
+	Name        string  `yaml:"name"`
+	Matched     *bool   `yaml:"matched"`
+	Color       *string `yaml:"color"`
+	Description *string `yaml:"description"`
+}
+
+// LoadFixtures reads fixtures from the paths, directories are searched for *.yml and *.yaml files
+func LoadFixtures(paths ...string) ([]*Fixture, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !entry.IsDir() && slices.Contains([]string{".yml", ".yaml"}, filepath.Ext(file)) {
+				files = append(files, file)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no fixture files found")
+	}
+
+	fixtures := make([]*Fixture, 0, len(files))
+
+	for _, file := range files {
+		fixture, err := LoadFixture(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// LoadFixture reads a single fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{path: path}
+
+	decoder := yaml.NewDecoder(strings.NewReader(string(raw)))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(fixture); err != nil {
+		return nil, fmt.Errorf("could not parse fixture %s: %w", path, err)
+	}
+
+	if len(fixture.Name) == 0 {
+		fixture.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	if len(fixture.Provider) == 0 {
+		fixture.Provider = "gitlab"
+	}
+
+	if len(fixture.Context) == 0 {
+		return nil, fmt.Errorf("fixture %s: 'context' is required", path)
+	}
+
+	return fixture, nil
+}
+
+// ContextPath returns the path of the evaluation context snapshot
+func (f *Fixture) ContextPath() string {
+	if filepath.IsAbs(f.Context) {
+		return f.Context
+	}
+
+	return filepath.Join(filepath.Dir(f.path), f.Context)
+}
@@ -0,0 +1,126 @@
+package ruletest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/ruletest"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+label:
+  - name: wip
+    color: "$red"
+    script: merge_request.title startsWith "WIP"
+  - name: stale
+    script: "false"
+
+actions:
+  - name: comment on WIP
+    group: wip
+    if: merge_request.title startsWith "WIP" && not has_stored_value("reminded")
+    then:
+      - action: comment
+        message: Please remove WIP
+  - name: close WIP
+    group: wip
+    if: merge_request.title startsWith "WIP" && not has_stored_value("reminded")
+    then:
+      - action: close
+`
+
+func loadFixture(t *testing.T, name string) *ruletest.Fixture {
+	t.Helper()
+
+	fixture, err := ruletest.LoadFixture(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return fixture
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.ParseFileString(testConfig)
+	require.NoError(t, err)
+
+	for _, name := range []string{"wip.yml", "reminded.yml"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ruletest.Run(t.Context(), cfg, loadFixture(t, name))
+			require.NoError(t, result.Err)
+			require.Empty(t, result.Diff)
+			require.True(t, result.Passed())
+		})
+	}
+}
+
+func TestRun_reportsMismatches(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.ParseFileString(testConfig)
+	require.NoError(t, err)
+
+	fixture := loadFixture(t, "wrong.yml")
+	require.Equal(t, "wrong", fixture.Name, "the name defaults to the file name")
+
+	result := ruletest.Run(t.Context(), cfg, fixture)
+	require.NoError(t, result.Err)
+	require.False(t, result.Passed())
+
+	// The expected label is not matched, an unknown label is never evaluated, an unexpected label
+	// is matched and the group only allows the first action
+	for _, want := range []string{`"stale: not matched"`, `"missing: not evaluated"`, `"wip: matched"`, `"close WIP"`, `"comment on WIP"`} {
+		require.Contains(t, result.Diff, want)
+	}
+}
+
+func TestRun_reportsErrors(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.ParseFileString(`label: [{name: broken, script: "nope("}]`)
+	require.NoError(t, err)
+
+	result := ruletest.Run(t.Context(), cfg, loadFixture(t, "wip.yml"))
+	require.ErrorContains(t, result.Err, "Configuration failed validation")
+	require.False(t, result.Passed())
+}
+
+func TestLoadFixtures(t *testing.T) {
+	t.Parallel()
+
+	fixtures, err := ruletest.LoadFixtures("testdata")
+	require.NoError(t, err)
+	require.Len(t, fixtures, 3, "only YAML files are fixtures")
+
+	fixtures, err = ruletest.LoadFixtures(filepath.Join("testdata", "wip.yml"))
+	require.NoError(t, err)
+	require.Len(t, fixtures, 1)
+	require.Equal(t, "gitlab", fixtures[0].Provider, "the provider defaults to gitlab")
+	require.Equal(t, filepath.Join("testdata", "wip-mr.json"), fixtures[0].ContextPath(), "the context is relative to the fixture")
+
+	_, err = ruletest.LoadFixtures(t.TempDir())
+	require.EqualError(t, err, "no fixture files found")
+}
+
+func TestLoadFixture_rejectsInvalidFixtures(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	missingContext := filepath.Join(dir, "missing-context.yml")
+	require.NoError(t, os.WriteFile(missingContext, []byte("name: nope\n"), 0o600))
+
+	_, err := ruletest.LoadFixture(missingContext)
+	require.ErrorContains(t, err, "'context' is required")
+
+	unknownKey := filepath.Join(dir, "unknown-key.yml")
+	require.NoError(t, os.WriteFile(unknownKey, []byte("context: a.json\nexpected: {}\n"), 0o600))
+
+	_, err = ruletest.LoadFixture(unknownKey)
+	require.ErrorContains(t, err, "field expected not found")
+}
@@ -0,0 +1,197 @@
+package ruletest
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/jippi/scm-engine/pkg/stdlib"
+	"github.com/jippi/scm-engine/pkg/tui"
+)
+
+// Result is the outcome of running a single fixture
+type Result struct {
+	Fixture *Fixture
+	// Diff between the expected and actual outcome (-want +got), empty when they match
+	Diff string
+	// Err is set when the fixture could not be evaluated at all
+	Err error
+}
+
+func (r Result) Passed() bool {
+	return r.Err == nil && len(r.Diff) == 0
+}
+
+// outcome is the comparable shape of an evaluation, one line per label and action
+type outcome struct {
+	Labels  []string
+	Actions []string
+}
+
+// LoadContext reads an evaluation context snapshot written by --dump-context for the provider
+func LoadContext(provider, path string) (scm.EvalContext, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read evaluation context: %w", err)
+	}
+	defer file.Close()
+
+	switch provider {
+	case "github":
+		return github.LoadContext(file)
+
+	case "gitlab":
+		return gitlab.LoadContext(file)
+
+	default:
+		return nil, fmt.Errorf("unknown provider %q - we only support 'github' and 'gitlab'", provider)
+	}
+}
+
+// Run evaluates the configuration against the fixture, without any network access or side effects
+func Run(ctx context.Context, cfg *config.Config, fixture *Fixture) Result {
+	result := Result{Fixture: fixture}
+
+	evalContext, err := LoadContext(fixture.Provider, fixture.ContextPath())
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	// Every fixture gets its own state, so fixtures can't influence each other
+	ctx = state.WithStore(ctx, state.NewMemoryStore())
+	ctx = state.WithProvider(ctx, fixture.Provider)
+	ctx = state.WithProjectID(ctx, "ruletest")
+	ctx = state.WithMergeRequestID(ctx, fixture.Name)
+	ctx = state.WithDryRun(ctx, true)
+
+	for key, value := range fixture.StoredValues {
+		if err := stdlib.SetStoredValue(ctx, key, value); err != nil {
+			result.Err = err
+
+			return result
+		}
+	}
+
+	if err := cfg.Lint(ctx, evalContext); err != nil {
+		result.Err = fmt.Errorf("Configuration failed validation: %w", err)
+
+		return result
+	}
+
+	ctx = config.WithConfig(ctx, cfg)
+
+	evalContext.SetContext(ctx)
+
+	labels, actions, err := cfg.Evaluate(ctx, evalContext)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	want, got := compare(fixture.Expect, labels, AppliedActions(evalContext, actions))
+
+	result.Diff = gocmp.Diff(want, got, cmpopts.EquateEmpty())
+
+	return result
+}
+
+// AppliedActions returns the actions that would be applied, only the first action within a group is applied per evaluation
+func AppliedActions(evalContext scm.EvalContext, actions config.Actions) config.Actions {
+	applied := make(config.Actions, 0, len(actions))
+
+	for _, action := range actions {
+		if evalContext.HasExecutedActionGroup(action.Group) {
+			continue
+		}
+
+		evalContext.TrackActionGroupExecution(action.Group)
+
+		applied = append(applied, action)
+	}
+
+	return applied
+}
+
+func compare(expect Expectation, labels []scm.EvaluationResult, actions config.Actions) (want, got outcome) {
+	results := make(map[string]scm.EvaluationResult, len(labels))
+	for _, label := range labels {
+		results[label.Name] = label
+	}
+
+	expected := make(map[string]bool, len(expect.Labels))
+
+	for _, label := range expect.Labels {
+		expected[label.Name] = true
+
+		matched := label.Matched == nil || *label.Matched
+
+		// Color variables like "$red" are resolved the same way as in the label configuration
+		if label.Color != nil {
+			label.Color = scm.Ptr(tui.Replace(*label.Color))
+		}
+
+		want.Labels = append(want.Labels, describeLabel(label.Name, matched, label.Color, label.Description))
+
+		result, ok := results[label.Name]
+		if !ok {
+			got.Labels = append(got.Labels, label.Name+": not evaluated")
+
+			continue
+		}
+
+		// Only compare the optional fields the fixture cares about
+		var color, description *string
+
+		if label.Color != nil {
+			color = &result.Color
+		}
+
+		if label.Description != nil {
+			description = &result.Description
+		}
+
+		got.Labels = append(got.Labels, describeLabel(result.Name, result.Matched, color, description))
+	}
+
+	// Any matched label not mentioned in the fixture is unexpected
+	for _, label := range labels {
+		if label.Matched && !expected[label.Name] {
+			got.Labels = append(got.Labels, describeLabel(label.Name, true, nil, nil))
+		}
+	}
+
+	want.Actions = expect.Actions
+
+	for _, action := range actions {
+		got.Actions = append(got.Actions, action.Name)
+	}
+
+	return want, got
+}
+
+func describeLabel(name string, matched bool, color, description *string) string {
+	line := name + ": matched"
+	if !matched {
+		line = name + ": not matched"
+	}
+
+	if color != nil {
+		line += fmt.Sprintf(", color %q", *color)
+	}
+
+	if description != nil {
+		line += fmt.Sprintf(", description %q", *description)
+	}
+
+	return line
+}
@@ -0,0 +1,64 @@
+package scm
+
+import (
+	"context"
+	"log/slog"
+
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// SelectAssignees picks the assignees for an 'assign' step from the author, the code owners or the static users.
+//
+// The static users are read from the staticField step field and turned into actors by staticActor.
+// Nothing is picked when the Merge Request already has assignees
+func SelectAssignees(ctx context.Context, evalContext EvalContext, step ActionStep, staticField string, staticActor func(string) Actor) (Actors, error) {
+	source, err := step.OptionalStringEnum("source", "author", "author", "codeowners", "static")
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := limitFromStep(step)
+	if err != nil {
+		return nil, err
+	}
+
+	// prevents misuse and situations where evaluate will reassign the Merge Request endlessly
+	existingAssignees := evalContext.GetAssignees()
+	if len(existingAssignees) > 0 {
+		slogctx.Debug(ctx, "Assignees already assigned", slog.Any("assignees", existingAssignees))
+
+		return nil, nil
+	}
+
+	var candidates Actors
+
+	switch source {
+	case "author":
+		candidates = Actors{evalContext.GetAuthor()}
+
+	case "codeowners":
+		// Teams can't be assigned to a Merge Request
+		for _, owner := range evalContext.GetCodeOwners() {
+			if !owner.IsTeam {
+				candidates = append(candidates, owner)
+			}
+		}
+
+	case "static":
+		values, err := step.RequiredStringSlice(staticField)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			candidates = append(candidates, staticActor(value))
+		}
+	}
+
+	// Pick at random when there are more candidates than assignees
+	if len(candidates) > limit {
+		return (&ReviewerSelection{Mode: "random", Limit: limit}).Select(ctx, candidates)
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha1" //nolint:gosec // git identifies blobs by their SHA1
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var commitSHAPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// Cache keeps the content of files read from the SCM in memory, so they are not downloaded on every evaluation.
+//
+// Entries are keyed by project, ref, path and git blob SHA, and point to the content by its blob SHA, so the same
+// file at different refs is only stored once. Entries expire after the TTL, and the least recently used entries
+// are evicted once the content is larger than the size limit
+type Cache struct {
+	mu sync.Mutex
+
+	ttl      time.Duration
+	maxBytes int
+
+	entries map[string]*list.Element
+	lru     *list.List
+	blobs   map[string]*blob
+	size    int
+
+	hits   uint64
+	misses uint64
+}
+
+type entry struct {
+	key     string
+	blobSHA string
+	expires time.Time
+}
+
+type blob struct {
+	content string
+	refs    int
+}
+
+// Stats are the cache counters, for measuring how effective the cache is
+type Stats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Bytes   int
+}
+
+// HitRate is the ratio of lookups served from the cache, between 0 and 1
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("hits", s.Hits),
+		slog.Uint64("misses", s.Misses),
+		slog.String("hit_rate", fmt.Sprintf("%.2f", s.HitRate())),
+		slog.Int("entries", s.Entries),
+		slog.Int("bytes", s.Bytes),
+	)
+}
+
+func New(ttl time.Duration, maxBytes int) *Cache {
+	return &Cache{
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+		blobs:    map[string]*blob{},
+	}
+}
+
+// Key returns the cache key of a file in a project at a ref.
+//
+// The blob SHA can be empty when the ref is a commit SHA, since the file can't change at that ref
+func Key(project, ref, path, blobSHA string) string {
+	if len(blobSHA) == 0 {
+		return fmt.Sprintf("%s@%s:%s", project, ref, path)
+	}
+
+	return fmt.Sprintf("%s@%s:%s#%s", project, ref, path, blobSHA)
+}
+
+// IsCommitSHA returns whether the ref is a full (SHA1 or SHA256) commit SHA, rather than a branch or tag
+func IsCommitSHA(ref string) bool {
+	return commitSHAPattern.MatchString(ref)
+}
+
+// BlobSHA returns the git blob SHA of the content, the same ID git (and the SCM) use for the file
+func BlobSHA(content string) string {
+	hash := sha1.New() //nolint:gosec // git identifies blobs by their SHA1
+	fmt.Fprintf(hash, "blob %d\x00", len(content))
+	hash.Write([]byte(content))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Get returns the content of the file, if it's cached and not expired
+func (c *Cache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok && time.Now().After(element.Value.(*entry).expires) { //nolint:forcetypeassert
+		c.remove(element)
+
+		ok = false
+	}
+
+	if !ok {
+		c.misses++
+
+		return "", false
+	}
+
+	c.hits++
+	c.lru.MoveToFront(element)
+
+	return c.blobs[element.Value.(*entry).blobSHA].content, true //nolint:forcetypeassert
+}
+
+// Set stores the content of the file, evicting the least recently used files if the cache grows too large
+func (c *Cache) Set(key, content string) {
+	// Files larger than the cache would evict everything else
+	if len(content) > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	blobSHA := BlobSHA(content)
+
+	existing, ok := c.blobs[blobSHA]
+	if !ok {
+		existing = &blob{content: content}
+		c.blobs[blobSHA] = existing
+		c.size += len(content)
+	}
+
+	existing.refs++
+
+	c.entries[key] = c.lru.PushFront(&entry{key: key, blobSHA: blobSHA, expires: time.Now().Add(c.ttl)})
+
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Stats returns the current cache counters
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.entries),
+		Bytes:   c.size,
+	}
+}
+
+// remove deletes the entry, and its content once no other entry points to it
+func (c *Cache) remove(element *list.Element) {
+	removed := c.lru.Remove(element).(*entry) //nolint:forcetypeassert
+
+	delete(c.entries, removed.key)
+
+	existing := c.blobs[removed.blobSHA]
+
+	existing.refs--
+	if existing.refs == 0 {
+		delete(c.blobs, removed.blobSHA)
+		c.size -= len(existing.content)
+	}
+}
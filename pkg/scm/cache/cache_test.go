@@ -0,0 +1,214 @@
+package cache_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/cache"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(time.Hour, 10)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+
+	c.Set("a", "12345")
+	c.Set("b", "12345") // same blob as 'a', so it's only stored once
+
+	content, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "12345", content)
+	require.Equal(t, cache.Stats{Hits: 1, Misses: 1, Entries: 2, Bytes: 5}, c.Stats())
+
+	// 'b' is the least recently used entry, but its content is shared with 'a'
+	c.Set("c", "abcde")
+	require.Equal(t, 10, c.Stats().Bytes)
+
+	// Evicts 'b' and then 'a', the least recently used entries, to make room
+	c.Set("d", "xyz")
+
+	_, ok = c.Get("a")
+	require.False(t, ok)
+
+	_, ok = c.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 8, c.Stats().Bytes)
+
+	// Files larger than the cache are never stored
+	c.Set("e", "this is too large")
+
+	_, ok = c.Get("e")
+	require.False(t, ok)
+
+	require.InDelta(t, 0.4, c.Stats().HitRate(), 0.001)
+}
+
+func TestCache_ttl(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(time.Nanosecond, 10)
+	c.Set("a", "12345")
+
+	time.Sleep(time.Millisecond)
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, cache.Stats{Misses: 1}, c.Stats())
+}
+
+func TestBlobSHA(t *testing.T) {
+	t.Parallel()
+
+	// echo -n 'label: []' | git hash-object --stdin
+	require.Equal(t, "0d5cb0cebb666913935559eae2a90b08aa62cd66", cache.BlobSHA("label: []"))
+}
+
+func TestIsCommitSHA(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, cache.IsCommitSHA("0d5cb0cebb666913935559eae2a90b08aa62cd66"))
+	require.False(t, cache.IsCommitSHA("main"))
+	require.False(t, cache.IsCommitSHA("HEAD"))
+	require.False(t, cache.IsCommitSHA("0d5cb0c"))
+}
+
+// countingClient serves the files from memory, and records the calls made
+type countingClient struct {
+	scm.Client
+
+	files     map[string]string
+	requested [][]string
+	lookups   int
+}
+
+func (c *countingClient) GetProjectFiles(_ context.Context, _ string, _ *string, files []string) (map[string]string, error) {
+	c.requested = append(c.requested, files)
+
+	result := map[string]string{}
+	for _, file := range files {
+		result[file] = c.files[file]
+	}
+
+	return result, nil
+}
+
+func (c *countingClient) GetProjectFileSHAs(_ context.Context, _ string, _ *string, files []string) (map[string]string, error) {
+	c.lookups++
+
+	result := map[string]string{}
+
+	for _, file := range files {
+		if content, ok := c.files[file]; ok {
+			result[file] = cache.BlobSHA(content)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *countingClient) MergeRequests() scm.MergeRequestClient {
+	return &countingMergeRequestClient{client: c}
+}
+
+type countingMergeRequestClient struct {
+	scm.MergeRequestClient
+
+	client *countingClient
+}
+
+func (c *countingMergeRequestClient) GetRemoteConfig(_ context.Context, name, ref string) (io.Reader, error) {
+	c.client.requested = append(c.client.requested, []string{ref + ":" + name})
+
+	return strings.NewReader(c.client.files[name]), nil
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = state.WithProjectID(t.Context(), "example/project")
+		upstream = &countingClient{files: map[string]string{"a.yml": "content of a.yml", "b.yml": "content of b.yml", ".scm-engine.yml": "label: []"}}
+		client   = cache.NewClient(upstream, cache.New(time.Hour, 1024))
+	)
+
+	files, err := client.GetProjectFiles(ctx, "shared", nil, []string{"a.yml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a.yml": "content of a.yml"}, files)
+
+	// Only the files missing from the cache are downloaded
+	files, err = client.GetProjectFiles(ctx, "shared", nil, []string{"a.yml", "b.yml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a.yml": "content of a.yml", "b.yml": "content of b.yml"}, files)
+
+	// The ref is part of the key
+	ref := "v1"
+
+	_, err = client.GetProjectFiles(ctx, "shared", &ref, []string{"a.yml"})
+	require.NoError(t, err)
+
+	// Files at a commit SHA never change, so their blob SHA isn't looked up
+	sha := "0d5cb0cebb666913935559eae2a90b08aa62cd66"
+
+	for range 2 {
+		file, err := client.MergeRequests().GetRemoteConfig(ctx, ".scm-engine.yml", sha)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "label: []", string(content))
+	}
+
+	require.Equal(t, [][]string{{"a.yml"}, {"b.yml"}, {"a.yml"}, {sha + ":.scm-engine.yml"}}, upstream.requested)
+	require.Equal(t, 3, upstream.lookups)
+}
+
+func TestClient_changed_content(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = state.WithProjectID(t.Context(), "example/project")
+		upstream = &countingClient{files: map[string]string{"a.yml": "v1", ".scm-engine.yml": "label: []"}}
+		client   = cache.NewClient(upstream, cache.New(time.Hour, 1024))
+	)
+
+	readConfig := func() string {
+		file, err := client.MergeRequests().GetRemoteConfig(ctx, ".scm-engine.yml", "HEAD")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	files, err := client.GetProjectFiles(ctx, "shared", nil, []string{"a.yml"})
+	require.NoError(t, err)
+	require.Equal(t, "v1", files["a.yml"])
+	require.Equal(t, "label: []", readConfig())
+
+	// Served from the cache while the files are unchanged
+	files, err = client.GetProjectFiles(ctx, "shared", nil, []string{"a.yml"})
+	require.NoError(t, err)
+	require.Equal(t, "v1", files["a.yml"])
+	require.Equal(t, "label: []", readConfig())
+	require.Len(t, upstream.requested, 2)
+
+	// The files change on the same ref, and are downloaded again
+	upstream.files["a.yml"] = "v2"
+	upstream.files[".scm-engine.yml"] = "label: [bug]"
+
+	files, err = client.GetProjectFiles(ctx, "shared", nil, []string{"a.yml"})
+	require.NoError(t, err)
+	require.Equal(t, "v2", files["a.yml"])
+	require.Equal(t, "label: [bug]", readConfig())
+	require.Len(t, upstream.requested, 4)
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// Ensure the cached client implements the [scm.Client]
+var _ scm.Client = (*Client)(nil)
+
+// Client wraps a [scm.Client], reading configuration files and included files through the [Cache]
+//
+// Files at a branch or tag are keyed by their blob SHA, looked up through [scm.BlobSHAReader], so changes
+// are picked up right away. Such files are never cached when the wrapped client can't look up blob SHAs
+type Client struct {
+	scm.Client
+
+	cache *Cache
+}
+
+func NewClient(client scm.Client, cache *Cache) *Client {
+	return &Client{Client: client, cache: cache}
+}
+
+// GetProjectFiles only reads the files missing from the cache from the wrapped client
+func (client *Client) GetProjectFiles(ctx context.Context, project string, ref *string, files []string) (map[string]string, error) {
+	strRef := "HEAD"
+	if ref != nil {
+		strRef = *ref
+	}
+
+	keys, err := client.keys(ctx, project, strRef, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result  = make(map[string]string, len(files))
+		missing []string
+	)
+
+	for _, file := range files {
+		key, ok := keys[file]
+		if !ok {
+			missing = append(missing, file)
+
+			continue
+		}
+
+		content, ok := client.lookup(ctx, key)
+		if !ok {
+			missing = append(missing, file)
+
+			continue
+		}
+
+		result[file] = content
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	downloaded, err := client.Client.GetProjectFiles(ctx, project, ref, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for file, content := range downloaded {
+		if key, ok := keys[file]; ok {
+			client.cache.Set(key, content)
+		}
+
+		result[file] = content
+	}
+
+	return result, nil
+}
+
+func (client *Client) MergeRequests() scm.MergeRequestClient {
+	return &mergeRequestClient{MergeRequestClient: client.Client.MergeRequests(), client: client}
+}
+
+// keys returns the cache key of the files, files that can't be cached are left out
+func (client *Client) keys(ctx context.Context, project, ref string, files []string) (map[string]string, error) {
+	keys := make(map[string]string, len(files))
+
+	// Files at a commit SHA never change
+	if IsCommitSHA(ref) {
+		for _, file := range files {
+			keys[file] = Key(project, ref, file, "")
+		}
+
+		return keys, nil
+	}
+
+	reader, ok := client.Client.(scm.BlobSHAReader)
+	if !ok {
+		return keys, nil
+	}
+
+	shas, err := reader.GetProjectFileSHAs(ctx, project, &ref, files)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up the blob SHA of files for the cache: %w", err)
+	}
+
+	for file, sha := range shas {
+		keys[file] = Key(project, ref, file, sha)
+	}
+
+	return keys, nil
+}
+
+// lookup reads from the cache, logging the outcome and the hit rate so far
+func (client *Client) lookup(ctx context.Context, key string) (string, bool) {
+	content, ok := client.cache.Get(key)
+
+	slogctx.Debug(ctx, "Remote configuration cache lookup", slog.String("cache_key", key), slog.Bool("cache_hit", ok), slog.Any("cache", client.cache.Stats()))
+
+	return content, ok
+}
+
+type mergeRequestClient struct {
+	scm.MergeRequestClient
+
+	client *Client
+}
+
+func (mr *mergeRequestClient) GetRemoteConfig(ctx context.Context, name, ref string) (io.Reader, error) {
+	// Without a ref the file is read from the Merge Request, which the key can't identify
+	if len(ref) == 0 {
+		return mr.MergeRequestClient.GetRemoteConfig(ctx, name, ref)
+	}
+
+	keys, err := mr.client.keys(ctx, state.ProjectID(ctx), ref, []string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	key, cacheable := keys[name]
+	if cacheable {
+		if content, ok := mr.client.lookup(ctx, key); ok {
+			return strings.NewReader(content), nil
+		}
+	}
+
+	file, err := mr.MergeRequestClient.GetRemoteConfig(ctx, name, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cacheable {
+		return file, nil
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	mr.client.cache.Set(key, string(content))
+
+	return strings.NewReader(string(content)), nil
+}
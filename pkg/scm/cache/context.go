@@ -0,0 +1,21 @@
+package cache
+
+import "context"
+
+type contextKey uint
+
+const (
+	cacheKey contextKey = iota
+)
+
+// WithCache configures the cache shared by every client created with the context
+func WithCache(ctx context.Context, cache *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey, cache)
+}
+
+func FromContext(ctx context.Context) *Cache {
+	// Caching is optional, so it's not an error for it to be missing
+	cache, _ := ctx.Value(cacheKey).(*Cache)
+
+	return cache
+}
@@ -3,31 +3,51 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	go_github "github.com/google/go-github/v90/github"
+	"github.com/hasura/go-graphql-client"
+	"github.com/jippi/scm-engine/pkg/integration/backstage"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+	"golang.org/x/oauth2"
 )
 
 // Ensure the GitLab client implements the [scm.Client]
 var _ scm.Client = (*Client)(nil)
 
+// Ensure the GitHub client can look up blob SHAs for the configuration cache
+var _ scm.BlobSHAReader = (*Client)(nil)
+
 // Client is a wrapper around the GitLab specific implementation of [scm.Client] interface
 type Client struct {
-	wrapped *go_github.Client
+	wrapped     *go_github.Client
+	backstage   *backstage.Client
+	outOfOffice scm.OutOfOfficeProvider
 
 	labels        *LabelClient
 	mergeRequests *MergeRequestClient
+
+	// botUsername is the login of the API token's user, looked up on first use
+	botUsername string
 }
 
 // NewClient creates a new GitLab client
-func NewClient(ctx context.Context) (*Client, error) {
-	client, err := go_github.NewClient(go_github.WithAuthToken(state.Token(ctx)))
+func NewClient(ctx context.Context, backstageClient *backstage.Client, outOfOffice scm.OutOfOfficeProvider) (*Client, error) {
+	baseURL := state.BaseURL(ctx)
+
+	client, err := go_github.NewClient(
+		go_github.WithAuthToken(state.Token(ctx)),
+		go_github.WithURLs(&baseURL, nil),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{wrapped: client}, nil
+	return &Client{wrapped: client, backstage: backstageClient, outOfOffice: outOfOffice}, nil
 }
 
 // Labels returns a client target at managing labels/tags
@@ -48,13 +68,110 @@ func (client *Client) MergeRequests() scm.MergeRequestClient {
 	return client.mergeRequests
 }
 
-func (client *Client) FindMergeRequestsForPeriodicEvaluation(context.Context, scm.MergeRequestListFilters) ([]scm.PeriodicEvaluationMergeRequest, error) {
-	return nil, errors.New("not implemented yet")
+func (client *Client) FindMergeRequestsForPeriodicEvaluation(ctx context.Context, filters scm.MergeRequestListFilters) ([]scm.PeriodicEvaluationMergeRequest, error) {
+	configFilePath := filters.SCMConfigurationFilePath
+	if len(configFilePath) == 0 {
+		configFilePath = ".scm-engine.yml"
+	}
+
+	var (
+		result    []scm.PeriodicEvaluationMergeRequest
+		variables = map[string]any{
+			// Read the config file from the default branch
+			"config_expression":   "HEAD:" + configFilePath,
+			"repositories_cursor": (*string)(nil),
+		}
+	)
+
+	for {
+		var response PeriodicEvaluationResult
+
+		if err := client.newGraphQLClient(ctx).Query(ctx, &response, variables); err != nil {
+			return nil, err
+		}
+
+		repositories := response.Viewer.Repositories
+
+		slogctx.Debug(ctx, fmt.Sprintf("Found %d repositories", len(repositories.Nodes)))
+
+		for _, repository := range repositories.Nodes {
+			if !repository.hasTopics(filters.OnlyProjectsWithTopics) {
+				continue
+			}
+
+			pullRequests, err := client.periodicEvaluationPullRequests(ctx, repository.NameWithOwner)
+			if err != nil {
+				return nil, err
+			}
+
+			slogctx.Debug(ctx, fmt.Sprintf("Repository %s has %d Pull Requests", repository.NameWithOwner, len(pullRequests)))
+
+			for _, pullRequest := range pullRequests {
+				if !pullRequest.matchesLabels(filters.OnlyMergeRequestsWithLabels, filters.IgnoreMergeRequestWithLabels) {
+					continue
+				}
+
+				item := scm.PeriodicEvaluationMergeRequest{
+					Project:        repository.NameWithOwner,
+					MergeRequestID: strconv.Itoa(pullRequest.Number),
+					SHA:            pullRequest.SHA,
+				}
+
+				// Only set the ConfigBlob struct if the config file exists in the repository
+				if repository.Object != nil {
+					item.ConfigBlob = repository.Object.Blob.Text
+				}
+
+				result = append(result, item)
+			}
+		}
+
+		if !repositories.PageInfo.HasNextPage {
+			break
+		}
+
+		variables["repositories_cursor"] = repositories.PageInfo.EndCursor
+	}
+
+	return result, nil
+}
+
+// periodicEvaluationPullRequests returns all open Pull Requests of the repository
+func (client *Client) periodicEvaluationPullRequests(ctx context.Context, nameWithOwner string) ([]PeriodicEvaluationPullRequestNode, error) {
+	owner, repo, _ := strings.Cut(nameWithOwner, "/")
+
+	var (
+		pullRequests []PeriodicEvaluationPullRequestNode
+		variables    = map[string]any{
+			"owner":  owner,
+			"repo":   repo,
+			"cursor": (*string)(nil),
+		}
+	)
+
+	for {
+		var response PeriodicEvaluationPullRequestsResult
+
+		if err := client.newGraphQLClient(ctx).Query(ctx, &response, variables); err != nil {
+			return nil, err
+		}
+
+		page := response.Repository.PullRequests
+		pullRequests = append(pullRequests, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+
+	return pullRequests, nil
 }
 
 // EvalContext creates a new evaluation context for GitLab specific usage
 func (client *Client) EvalContext(ctx context.Context) (scm.EvalContext, error) {
-	res, err := NewContext(ctx, "https://api.github.com/", state.Token(ctx))
+	res, err := NewContext(ctx, graphqlBaseURL(client.wrapped.BaseURL()), state.Token(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +189,107 @@ func (client *Client) Stop(ctx context.Context, err error, allowPipelineFailure
 	return nil
 }
 
-// Get Project Files
+// GetProjectFiles reads the files from another repository, the project must be in 'owner/repo' format
 func (client *Client) GetProjectFiles(ctx context.Context, project string, ref *string, files []string) (map[string]string, error) {
-	return nil, errors.New("not implemented yet")
+	if len(project) == 0 {
+		return nil, errors.New("Missing required 'project' value for include")
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("Missing list of files to include from project [%s]", project)
+	}
+
+	owner, repo, ok := strings.Cut(project, "/")
+	if !ok || len(owner) == 0 || len(repo) == 0 {
+		return nil, fmt.Errorf("project [%s] must be in 'owner/repo' format", project)
+	}
+
+	options := &go_github.RepositoryContentGetOptions{}
+
+	// GitHub reads from the default branch when no ref is provided
+	if ref != nil && *ref != "HEAD" {
+		options.Ref = *ref
+	}
+
+	fileContents := make(map[string]string, len(files))
+
+	for _, file := range files {
+		content, _, _, err := client.wrapped.Repositories.GetContents(ctx, owner, repo, file, options)
+		if err != nil {
+			return nil, fmt.Errorf("configuration file [%s] in project [%s] does not exist (or could not be read): %w", file, project, err)
+		}
+
+		if content == nil {
+			return nil, fmt.Errorf("configuration file [%s] in project [%s] is a directory, expected a file", file, project)
+		}
+
+		val, err := content.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("configuration file [%s] in project [%s] could not be decoded: %w", file, project, err)
+		}
+
+		if len(val) == 0 {
+			return nil, fmt.Errorf("configuration file [%s] in project [%s] is empty", file, project)
+		}
+
+		fileContents[file] = val
+	}
+
+	return fileContents, nil
+}
+
+// GetProjectFileSHAs looks up the blob SHA of the files in another repository, the project must be in 'owner/repo' format
+func (client *Client) GetProjectFileSHAs(ctx context.Context, project string, ref *string, files []string) (map[string]string, error) {
+	owner, repo, ok := strings.Cut(project, "/")
+	if !ok || len(owner) == 0 || len(repo) == 0 {
+		return nil, fmt.Errorf("project [%s] must be in 'owner/repo' format", project)
+	}
+
+	// GitHub resolves 'HEAD' to the default branch
+	strRef := "HEAD"
+	if ref != nil && len(*ref) > 0 {
+		strRef = *ref
+	}
+
+	shas := make(map[string]string, len(files))
+
+	for _, file := range files {
+		var query struct {
+			Repository struct {
+				Object struct {
+					Oid string `graphql:"oid"`
+				} `graphql:"object(expression: $expression)"`
+			} `graphql:"repository(owner: $owner, name: $repo)"`
+		}
+
+		variables := map[string]any{
+			"owner":      owner,
+			"repo":       repo,
+			"expression": strRef + ":" + file,
+		}
+
+		if err := client.newGraphQLClient(ctx).Query(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("could not read the blob SHA of [%s] in project [%s]: %w", file, project, err)
+		}
+
+		// The object is null when the file doesn't exist
+		if len(query.Repository.Object.Oid) > 0 {
+			shas[file] = query.Repository.Object.Oid
+		}
+	}
+
+	return shas, nil
+}
+
+func (client *Client) newGraphQLClient(ctx context.Context) *graphql.Client {
+	httpClient := oauth2.NewClient(
+		ctx,
+		oauth2.StaticTokenSource(
+			&oauth2.Token{
+				AccessToken: state.Token(ctx),
+			},
+		),
+	)
+
+	return graphql.NewClient(graphqlBaseURL(client.wrapped.BaseURL()), httpClient)
 }
@@ -2,11 +2,10 @@ package github
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log/slog"
 
 	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/config"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
 	slogctx "github.com/veqryn/slog-context"
@@ -21,6 +20,15 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 	}
 
 	switch action {
+	case "update_description":
+		return config.UpdateDescription(ctx, evalContext, update, step)
+
+	case "store_value":
+		return config.StoreValue(ctx, evalContext, step)
+
+	case "delete_stored_value":
+		return config.DeleteStoredValue(ctx, step)
+
 	case "add_label":
 		name, err := step.RequiredString("label")
 		if err != nil {
@@ -57,6 +65,9 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 	case "reopen":
 		update.StateEvent = scm.Ptr("reopen")
 
+	case "rebase":
+		return config.Rebase(update, step)
+
 	case "lock_discussion":
 		update.DiscussionLocked = scm.Ptr(true)
 
@@ -88,26 +99,37 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 		return err
 
 	case "comment":
-		msg, err := step.RequiredString("message")
-		if err != nil {
-			return err
-		}
+		return config.Comment(ctx, evalContext, step, c)
 
-		if len(msg) == 0 {
-			return errors.New("step field 'message' must not be an empty string")
-		}
+	case "discussion":
+		return config.Discussion(ctx, evalContext, step, c)
 
-		if state.IsDryRun(ctx) {
-			slogctx.Info(ctx, "Commenting on MR", slog.String("message", msg))
+	case "resolve_discussions":
+		return config.ResolveDiscussions(ctx, step, c)
 
-			return nil
-		}
+	case "merge":
+		return c.Merge(ctx, evalContext, step)
 
-		_, _, err = c.wrapped.PullRequests.CreateComment(ctx, owner, repo, state.MergeRequestIDInt(ctx), &go_github.PullRequestComment{
-			Body: scm.Ptr(msg),
-		})
+	case "auto_merge":
+		return c.AutoMerge(ctx, evalContext, step)
 
-		return err
+	case "assign":
+		return c.Assign(ctx, evalContext, update, step)
+
+	case "set_milestone":
+		return c.SetMilestone(ctx, update, step)
+
+	case "update_title":
+		return config.UpdateTitle(ctx, evalContext, update, step)
+
+	case "mark_draft":
+		setDraft(evalContext, update, true)
+
+	case "mark_ready":
+		setDraft(evalContext, update, false)
+
+	case "assign_reviewers":
+		return c.AssignReviewers(ctx, evalContext, update, step)
 
 	default:
 		return fmt.Errorf("GitHub client does not know how to apply action %q", action)
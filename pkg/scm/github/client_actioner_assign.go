@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+func (c *Client) AssignReviewers(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	source, err := step.OptionalStringEnum("source", "codeowners", "codeowners", "backstage", "static")
+	if err != nil {
+		return err
+	}
+
+	selection, err := scm.NewReviewerSelection(step)
+	if err != nil {
+		return err
+	}
+
+	selection.OpenReviews = c.openReviews
+	selection.OutOfOffice = c.outOfOffice
+
+	// prevents misuse and situations where evaluate will assign reviewers endlessly
+	existingReviewers := evalContext.GetReviewers()
+	if len(existingReviewers) > 0 {
+		slogctx.Debug(ctx, "Reviewers already assigned", slog.Any("reviewers", existingReviewers))
+
+		return nil
+	}
+
+	var eligibleReviewers []scm.Actor
+
+	switch source {
+	case "codeowners":
+		eligibleReviewers = evalContext.GetCodeOwners()
+
+	case "backstage":
+		if c.backstage == nil {
+			slogctx.Warn(ctx, "Backstage client not initialized and source is backstage, skipping")
+
+			break
+		}
+
+		owners, err := c.backstage.GetOwnersForGitHubProject(ctx, state.ProjectID(ctx))
+		if err != nil {
+			return err
+		}
+
+		author := evalContext.GetAuthor().Username
+		for _, owner := range owners {
+			if author != owner.Username {
+				eligibleReviewers = append(eligibleReviewers, owner)
+			}
+		}
+
+	case "static":
+		usernames, err := step.RequiredStringSlice("usernames")
+		if err != nil {
+			return err
+		}
+
+		for _, username := range usernames {
+			eligibleReviewers = append(eligibleReviewers, scm.Actor{Username: username, IsTeam: strings.Contains(username, "/")})
+		}
+	}
+
+	if len(eligibleReviewers) == 0 {
+		slogctx.Debug(ctx, "No eligible reviewers found")
+
+		return nil
+	}
+
+	reviewers, err := selection.Select(ctx, eligibleReviewers)
+	if err != nil {
+		return err
+	}
+
+	if len(reviewers) == 0 {
+		slogctx.Debug(ctx, "No eligible reviewers left after exclusions")
+
+		return nil
+	}
+
+	// GitHub requests reviews from users by login, and from teams by their slug within the organization
+	request := go_github.ReviewersRequest{}
+
+	for _, reviewer := range reviewers {
+		if reviewer.IsTeam {
+			_, slug, _ := strings.Cut(reviewer.Username, "/")
+			request.TeamReviewers = append(request.TeamReviewers, slug)
+
+			continue
+		}
+
+		request.Reviewers = append(request.Reviewers, reviewer.Username)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "Requesting reviewers", slog.String("source", source), slog.Int("limit", selection.Limit), slog.String("mode", selection.Mode), slog.Any("reviewers", reviewers))
+
+		return nil
+	}
+
+	owner, repo := ownerAndRepo(ctx)
+
+	_, _, err = c.wrapped.PullRequests.RequestReviewers(ctx, owner, repo, state.MergeRequestIDInt(ctx), request)
+
+	return err
+}
+
+// Assign applies the 'assign' action step, assigning the author, code owners or static users to the Pull Request
+func (c *Client) Assign(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	assignees, err := scm.SelectAssignees(ctx, evalContext, step, "usernames", func(username string) scm.Actor { return scm.Actor{Username: username} })
+	if err != nil {
+		return err
+	}
+
+	usernames := make([]string, 0, len(assignees))
+
+	for _, assignee := range assignees {
+		if len(assignee.Username) > 0 {
+			usernames = append(usernames, assignee.Username)
+		}
+	}
+
+	if len(usernames) == 0 {
+		slogctx.Debug(ctx, "No eligible assignees found")
+
+		return nil
+	}
+
+	update.Assignees = &usernames
+
+	return nil
+}
+
+// openReviews counts the open Pull Requests where a review is requested from the actor
+func (c *Client) openReviews(ctx context.Context, actor scm.Actor) (int, error) {
+	query := "is:pr is:open review-requested:" + actor.Username
+	if actor.IsTeam {
+		query = "is:pr is:open team-review-requested:" + actor.Username
+	}
+
+	result, _, err := c.wrapped.Search.Issues(ctx, query, &go_github.SearchOptions{ListOptions: go_github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.GetTotal(), nil
+}
@@ -0,0 +1,251 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type evalContextMock struct {
+	mock.Mock
+}
+
+func (c *evalContextMock) IsValid() bool {
+	return c != nil
+}
+
+func (c *evalContextMock) SetWebhookEvent(in any) {
+	c.Called(in)
+}
+
+func (c *evalContextMock) SetContext(ctx context.Context) {
+	c.Called(ctx)
+}
+
+func (c *evalContextMock) GetDescription() string {
+	args := c.Called()
+
+	return args.String(0)
+}
+
+func (c *evalContextMock) GetTitle() string {
+	args := c.Called()
+
+	return args.String(0)
+}
+
+func (c *evalContextMock) CanUseConfigurationFileFromChangeRequest(ctx context.Context) bool {
+	args := c.Called(ctx)
+
+	return args.Bool(0)
+}
+
+func (c *evalContextMock) AllowPipelineFailure(ctx context.Context) bool {
+	args := c.Called(ctx)
+
+	return args.Bool(0)
+}
+
+func (c *evalContextMock) TrackActionGroupExecution(group string) {
+	c.Called(group)
+}
+
+func (c *evalContextMock) HasExecutedActionGroup(group string) bool {
+	args := c.Called(group)
+
+	return args.Bool(0)
+}
+
+func (c *evalContextMock) GetCodeOwners() scm.Actors {
+	args := c.Called()
+
+	if actors, ok := args.Get(0).(scm.Actors); ok {
+		return actors
+	}
+
+	return nil
+}
+
+func (c *evalContextMock) GetReviewers() scm.Actors {
+	args := c.Called()
+
+	if actors, ok := args.Get(0).(scm.Actors); ok {
+		return actors
+	}
+
+	return nil
+}
+
+func (c *evalContextMock) GetAssignees() scm.Actors {
+	args := c.Called()
+
+	if actors, ok := args.Get(0).(scm.Actors); ok {
+		return actors
+	}
+
+	return nil
+}
+
+func (c *evalContextMock) GetAuthor() scm.Actor {
+	args := c.Called()
+
+	if actor, ok := args.Get(0).(scm.Actor); ok {
+		return actor
+	}
+
+	return scm.Actor{}
+}
+
+func (c *evalContextMock) GetLabels() []string {
+	args := c.Called()
+
+	if labels, ok := args.Get(0).([]string); ok {
+		return labels
+	}
+
+	return nil
+}
+
+func (c *evalContextMock) GetModifiedFiles() []string {
+	args := c.Called()
+
+	if files, ok := args.Get(0).([]string); ok {
+		return files
+	}
+
+	return nil
+}
+
+func TestAssignReviewers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                      string
+		step                      config.ActionStep
+		mockGetReviewersResponse  scm.Actors
+		mockGetCodeOwnersResponse scm.Actors
+		wantReviewers             []string
+		wantTeamReviewers         []string
+		wantErr                   error
+	}{
+		{
+			name:                      "should default to codeowners when no source provided",
+			step:                      config.ActionStep{"limit": 2},
+			mockGetCodeOwnersResponse: nil,
+		},
+		{
+			name: "should request users and teams from codeowners",
+			step: config.ActionStep{"source": "codeowners", "limit": 6, "mode": "random"},
+			mockGetCodeOwnersResponse: scm.Actors{
+				{Username: "alice"},
+				{Username: "jippi/core", IsTeam: true},
+			},
+			wantReviewers:     []string{"alice"},
+			wantTeamReviewers: []string{"core"},
+		},
+		{
+			name: "should honor the limit",
+			step: config.ActionStep{"source": "codeowners", "limit": 1},
+			mockGetCodeOwnersResponse: scm.Actors{
+				{Username: "alice"},
+				{Username: "bob"},
+				{Username: "charlie"},
+			},
+			wantReviewers: []string{"alice"},
+		},
+		{
+			name:          "should request static usernames",
+			step:          config.ActionStep{"source": "static", "usernames": []string{"alice", "bob"}, "limit": 5},
+			wantReviewers: []string{"alice", "bob"},
+		},
+		{
+			name:    "should error when usernames is missing for static source",
+			step:    config.ActionStep{"source": "static"},
+			wantErr: errors.New("Required 'step' key 'usernames' is missing"),
+		},
+		{
+			name:                      "should not request reviewers if reviewers already exist",
+			step:                      config.ActionStep{"source": "codeowners"},
+			mockGetReviewersResponse:  scm.Actors{{Username: "existing"}},
+			mockGetCodeOwnersResponse: scm.Actors{{Username: "alice"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := new(evalContextMock)
+			evalContext.On("GetReviewers").Return(tt.mockGetReviewersResponse)
+			evalContext.On("GetCodeOwners").Return(tt.mockGetCodeOwnersResponse)
+
+			var received *go_github.ReviewersRequest
+
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = &go_github.ReviewersRequest{}
+				if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"number":42}`))
+			}))
+			t.Cleanup(upstream.Close)
+
+			ctx := state.WithToken(t.Context(), "token")
+			ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+			ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+			ctx = state.WithMergeRequestID(ctx, "42")
+			ctx = state.WithDryRun(ctx, false)
+			ctx = state.WithRandomSeed(ctx, 1)
+
+			client, err := github.NewClient(ctx, nil, nil)
+			require.NoError(t, err)
+
+			err = client.AssignReviewers(ctx, evalContext, &scm.UpdateMergeRequestOptions{}, tt.step)
+			require.Equal(t, tt.wantErr, err)
+
+			if tt.wantReviewers == nil && tt.wantTeamReviewers == nil {
+				require.Nil(t, received, "no reviewers should be requested")
+
+				return
+			}
+
+			require.NotNil(t, received)
+			require.Equal(t, tt.wantReviewers, received.Reviewers)
+			require.Equal(t, tt.wantTeamReviewers, received.TeamReviewers)
+		})
+	}
+}
+
+func TestAssignReviewers_dryRun(t *testing.T) {
+	t.Parallel()
+
+	evalContext := new(evalContextMock)
+	evalContext.On("GetReviewers").Return(nil)
+
+	ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+	ctx = state.WithDryRun(ctx, true)
+	ctx = state.WithRandomSeed(ctx, 1)
+
+	// The zero client has no API access, so any request would panic
+	err := (&github.Client{}).AssignReviewers(ctx, evalContext, &scm.UpdateMergeRequestOptions{}, config.ActionStep{
+		"source":    "static",
+		"usernames": []string{"alice"},
+	})
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// mergeableStates are the merge state statuses where GitHub allows merging right away
+var mergeableStates = []MergeStateStatus{
+	MergeStateStatusClean,
+	MergeStateStatusHasHooks,
+	MergeStateStatusUnstable,
+}
+
+// Merge applies the 'merge' action step, merging the Pull Request right away
+func (c *Client) Merge(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) error {
+	owner, repo := ownerAndRepo(ctx)
+
+	method, sha, err := mergeOptions(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	pullRequest := pullRequestFromContext(evalContext)
+	if pullRequest == nil {
+		return errors.New("Pull Request is not available in the evaluation context")
+	}
+
+	if pullRequest.Mergeable != MergeableStateMergeable || !slices.Contains(mergeableStates, pullRequest.MergeStateStatus) {
+		return fmt.Errorf("Pull Request is not mergeable, the mergeable state is %s and the merge state status is %s", pullRequest.Mergeable, pullRequest.MergeStateStatus)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Merging PR", slog.String("merge_method", method))
+
+		return nil
+	}
+
+	_, _, err = c.wrapped.PullRequests.Merge(ctx, owner, repo, state.MergeRequestIDInt(ctx), "", &go_github.PullRequestOptions{
+		MergeMethod: method,
+		SHA:         sha,
+	})
+
+	return err
+}
+
+// AutoMerge applies the 'auto_merge' action step, enabling (or disabling) auto-merge once the requirements are met
+func (c *Client) AutoMerge(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) error {
+	mode, err := step.OptionalStringEnum("mode", "set", "set", "cancel")
+	if err != nil {
+		return err
+	}
+
+	method, sha, err := mergeOptions(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	pullRequest := pullRequestFromContext(evalContext)
+	if pullRequest == nil {
+		return errors.New("Pull Request is not available in the evaluation context")
+	}
+
+	if mode == "cancel" {
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Disabling auto-merge of PR")
+
+			return nil
+		}
+
+		var mutation struct {
+			DisablePullRequestAutoMerge struct {
+				ClientMutationID *string `graphql:"clientMutationId"`
+			} `graphql:"disablePullRequestAutoMerge(input: $input)"`
+		}
+
+		return c.newGraphQLClient(ctx).Mutate(ctx, &mutation, map[string]any{
+			"input": DisablePullRequestAutoMergeInput{PullRequestID: pullRequest.ID},
+		})
+	}
+
+	if githubContext, ok := evalContext.(*Context); ok && githubContext.Repository != nil && !githubContext.Repository.AutoMergeAllowed {
+		return errors.New("Pull Request can't be set to auto-merge, auto-merge is not allowed in the repository")
+	}
+
+	if pullRequest.IsDraft || pullRequest.Mergeable == MergeableStateConflicting {
+		return fmt.Errorf("Pull Request can't be set to auto-merge, the mergeable state is %s (draft: %t)", pullRequest.Mergeable, pullRequest.IsDraft)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Enabling auto-merge of PR", slog.String("merge_method", method))
+
+		return nil
+	}
+
+	input := EnablePullRequestAutoMergeInput{
+		PullRequestID: pullRequest.ID,
+		MergeMethod:   PullRequestMergeMethod(strings.ToUpper(method)),
+	}
+
+	if len(sha) > 0 {
+		input.ExpectedHeadOid = scm.Ptr(GitObjectID(sha))
+	}
+
+	var mutation struct {
+		EnablePullRequestAutoMerge struct {
+			ClientMutationID *string `graphql:"clientMutationId"`
+		} `graphql:"enablePullRequestAutoMerge(input: $input)"`
+	}
+
+	return c.newGraphQLClient(ctx).Mutate(ctx, &mutation, map[string]any{"input": input})
+}
+
+// mergeOptions reads the 'squash' and 'sha_guard' step fields, returning the merge method and expected head SHA.
+//
+// With 'sha_guard' (default) the merge fails if commits were pushed after the evaluated commit
+func mergeOptions(ctx context.Context, step scm.ActionStep) (string, string, error) {
+	squash, err := step.OptionalBool("squash", false)
+	if err != nil {
+		return "", "", err
+	}
+
+	shaGuard, err := step.OptionalBool("sha_guard", true)
+	if err != nil {
+		return "", "", err
+	}
+
+	method := "merge"
+	if squash {
+		method = "squash"
+	}
+
+	if !shaGuard {
+		return method, "", nil
+	}
+
+	return method, state.CommitSHA(ctx), nil
+}
+
+func pullRequestFromContext(evalContext scm.EvalContext) *ContextPullRequest {
+	githubContext, ok := evalContext.(*Context)
+	if !ok {
+		return nil
+	}
+
+	return githubContext.PullRequest
+}
+
+// EnablePullRequestAutoMergeInput is the input of the 'enablePullRequestAutoMerge' GraphQL mutation
+type EnablePullRequestAutoMergeInput struct {
+	PullRequestID   string                 `json:"pullRequestId"`
+	MergeMethod     PullRequestMergeMethod `json:"mergeMethod"`
+	ExpectedHeadOid *GitObjectID           `json:"expectedHeadOid,omitempty"`
+}
+
+// DisablePullRequestAutoMergeInput is the input of the 'disablePullRequestAutoMerge' GraphQL mutation
+type DisablePullRequestAutoMergeInput struct {
+	PullRequestID string `json:"pullRequestId"`
+}
+
+// PullRequestMergeMethod is the GraphQL enum of merge methods (e.g. SQUASH)
+type PullRequestMergeMethod string
+
+// GitObjectID is the GraphQL scalar of a git object SHA
+type GitObjectID string
@@ -0,0 +1,84 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyStep_merge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		step             config.ActionStep
+		pullRequest      github.ContextPullRequest
+		autoMergeAllowed bool
+		wantErr          string
+	}{
+		{
+			name:        "merge a mergeable PR",
+			step:        config.ActionStep{"action": "merge", "squash": true},
+			pullRequest: github.ContextPullRequest{Mergeable: github.MergeableStateMergeable, MergeStateStatus: github.MergeStateStatusClean},
+		},
+		{
+			name:        "merge requires a mergeable PR",
+			step:        config.ActionStep{"action": "merge"},
+			pullRequest: github.ContextPullRequest{Mergeable: github.MergeableStateMergeable, MergeStateStatus: github.MergeStateStatusBlocked},
+			wantErr:     "Pull Request is not mergeable, the mergeable state is MERGEABLE and the merge state status is BLOCKED",
+		},
+		{
+			name:             "auto_merge a blocked PR",
+			step:             config.ActionStep{"action": "auto_merge"},
+			pullRequest:      github.ContextPullRequest{Mergeable: github.MergeableStateMergeable, MergeStateStatus: github.MergeStateStatusBlocked},
+			autoMergeAllowed: true,
+		},
+		{
+			name:        "auto_merge must be allowed in the repository",
+			step:        config.ActionStep{"action": "auto_merge"},
+			pullRequest: github.ContextPullRequest{Mergeable: github.MergeableStateMergeable},
+			wantErr:     "auto-merge is not allowed in the repository",
+		},
+		{
+			name:             "auto_merge with conflicts",
+			step:             config.ActionStep{"action": "auto_merge"},
+			pullRequest:      github.ContextPullRequest{Mergeable: github.MergeableStateConflicting},
+			autoMergeAllowed: true,
+			wantErr:          "Pull Request can't be set to auto-merge, the mergeable state is CONFLICTING (draft: false)",
+		},
+		{
+			name:        "cancel auto_merge",
+			step:        config.ActionStep{"action": "auto_merge", "mode": "cancel"},
+			pullRequest: github.ContextPullRequest{Mergeable: github.MergeableStateConflicting},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Dry run, so nothing is sent to the (nil) GitHub client
+			ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+			ctx = state.WithCommitSHA(ctx, "c0ffee")
+			ctx = state.WithDryRun(ctx, true)
+
+			evalContext := &github.Context{
+				Repository:  &github.ContextRepository{AutoMergeAllowed: tt.autoMergeAllowed},
+				PullRequest: &tt.pullRequest,
+			}
+
+			err := (&github.Client{}).ApplyStep(ctx, evalContext, &scm.UpdateMergeRequestOptions{}, tt.step)
+			if len(tt.wantErr) > 0 {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -190,32 +190,41 @@ func TestApplyStep_dryRunSkipsAPICalls(t *testing.T) {
 	}
 }
 
-// update_description and assign_reviewers are implemented for GitLab only. They
-// are documented without a provider caveat, so this records the actual GitHub
-// behaviour: the action is rejected rather than silently ignored.
-func TestApplyStep_gitLabOnlyActionsAreRejected(t *testing.T) {
+// The replacement logic is shared with GitLab (and tested there), this only
+// covers that the action is wired up for GitHub Pull Request bodies.
+func TestApplyStep_updateDescription(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		name string
-		step config.ActionStep
-	}{
-		{
-			name: "update_description",
-			step: config.ActionStep{"action": "update_description", "replace": config.ActionStep{"a": `"b"`}},
-		},
-		{
-			name: "assign_reviewers",
-			step: config.ActionStep{"action": "assign_reviewers", "source": "codeowners"},
-		},
-	}
+	evalContext := new(evalContextMock)
+	evalContext.On("GetDescription").Return("Pull Request ${{NUMBER}} ${{STATE}}")
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
+	ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+	ctx = state.WithDryRun(ctx, false)
 
-			_, err := applyStep(t, tt.step)
-			require.ErrorContains(t, err, "GitHub client does not know how to apply action")
-		})
-	}
+	client := &github.Client{}
+	update := &scm.UpdateMergeRequestOptions{}
+
+	require.NoError(t, client.ApplyStep(ctx, evalContext, update, config.ActionStep{
+		"action":  "update_description",
+		"replace": config.ActionStep{"${{NUMBER}}": `"#42"`},
+	}))
+
+	require.NoError(t, client.ApplyStep(ctx, evalContext, update, config.ActionStep{
+		"action":  "update_description",
+		"replace": config.ActionStep{"${{STATE}}": `"is ready"`},
+	}))
+
+	require.Equal(t, scm.Ptr("Pull Request #42 is ready"), update.Description)
+}
+
+func TestApplyStep_updateDescription_requiresReplace(t *testing.T) {
+	t.Parallel()
+
+	evalContext := new(evalContextMock)
+	evalContext.On("GetDescription").Return("body")
+
+	ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+
+	err := (&github.Client{}).ApplyStep(ctx, evalContext, &scm.UpdateMergeRequestOptions{}, config.ActionStep{"action": "update_description"})
+	require.ErrorContains(t, err, "'step' key 'replace' is missing")
 }
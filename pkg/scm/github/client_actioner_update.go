@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+)
+
+// SetMilestone applies the 'set_milestone' action step, resolving the milestone title to its number
+func (c *Client) SetMilestone(ctx context.Context, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	owner, repo := ownerAndRepo(ctx)
+
+	title, err := step.RequiredString("milestone")
+	if err != nil {
+		return err
+	}
+
+	opts := &go_github.MilestoneListOptions{
+		State:       "all",
+		ListOptions: go_github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		milestones, resp, err := c.wrapped.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return fmt.Errorf("could not list milestones: %w", err)
+		}
+
+		for _, milestone := range milestones {
+			if milestone.GetTitle() == title {
+				update.MilestoneID = scm.Ptr(milestone.GetNumber())
+
+				return nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return fmt.Errorf("milestone %q does not exist in the repository %s", title, state.ProjectID(ctx))
+}
+
+// setDraft applies the 'mark_draft' and 'mark_ready' action steps
+func setDraft(evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, draft bool) {
+	// Nothing to change if the Pull Request already is in the requested state
+	if pullRequest := pullRequestFromContext(evalContext); pullRequest != nil && pullRequest.IsDraft == draft {
+		update.Draft = nil
+
+		return
+	}
+
+	update.Draft = scm.Ptr(draft)
+}
+
+// ConvertPullRequestToDraftInput is the input of the 'convertPullRequestToDraft' GraphQL mutation
+type ConvertPullRequestToDraftInput struct {
+	PullRequestID string `json:"pullRequestId"`
+}
+
+// MarkPullRequestReadyForReviewInput is the input of the 'markPullRequestReadyForReview' GraphQL mutation
+type MarkPullRequestReadyForReviewInput struct {
+	PullRequestID string `json:"pullRequestId"`
+}
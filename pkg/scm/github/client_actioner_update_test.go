@@ -0,0 +1,125 @@
+package github_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyStep_draft(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		action  string
+		isDraft bool
+		want    *bool
+	}{
+		{name: "mark_draft on a ready PR", action: "mark_draft", isDraft: false, want: scm.Ptr(true)},
+		{name: "mark_draft on a draft PR", action: "mark_draft", isDraft: true, want: nil},
+		{name: "mark_ready on a draft PR", action: "mark_ready", isDraft: true, want: scm.Ptr(false)},
+		{name: "mark_ready on a ready PR", action: "mark_ready", isDraft: false, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := &github.Context{PullRequest: &github.ContextPullRequest{IsDraft: tt.isDraft}}
+			update := &scm.UpdateMergeRequestOptions{}
+
+			ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+
+			err := (&github.Client{}).ApplyStep(ctx, evalContext, update, config.ActionStep{"action": tt.action})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, update.Draft)
+		})
+	}
+}
+
+func TestApplyStep_assign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		step      config.ActionStep
+		assignees []github.ContextActorUser
+		want      *[]string
+	}{
+		{
+			name: "defaults to the author",
+			step: config.ActionStep{"action": "assign"},
+			want: &[]string{"jippi"},
+		},
+		{
+			name: "static users",
+			step: config.ActionStep{"action": "assign", "source": "static", "usernames": []any{"alice"}},
+			want: &[]string{"alice"},
+		},
+		{
+			name:      "already assigned",
+			step:      config.ActionStep{"action": "assign"},
+			assignees: []github.ContextActorUser{{Login: "bob"}},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := &github.Context{PullRequest: &github.ContextPullRequest{
+				ResponseAuthor:    &github.ContextActor{Typename: "User", User: &github.ContextActorUser{Login: "jippi"}},
+				ResponseAssignees: &github.ContextActorUserConnection{Nodes: tt.assignees},
+			}}
+			update := &scm.UpdateMergeRequestOptions{}
+
+			ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+
+			err := (&github.Client{}).ApplyStep(ctx, evalContext, update, tt.step)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, update.Assignees)
+		})
+	}
+}
+
+func TestApplyStep_setMilestone(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		// The milestone is on the second page
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+			fmt.Fprint(w, `[{"number": 1, "title": "v1.0.0"}]`)
+
+		default:
+			fmt.Fprint(w, `[{"number": 3, "title": "v1.2.0"}]`)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	update := &scm.UpdateMergeRequestOptions{}
+
+	require.NoError(t, client.ApplyStep(ctx, nil, update, config.ActionStep{"action": "set_milestone", "milestone": "v1.2.0"}))
+	require.Equal(t, scm.Ptr(3), update.MilestoneID)
+
+	err = client.ApplyStep(ctx, nil, &scm.UpdateMergeRequestOptions{}, config.ActionStep{"action": "set_milestone", "milestone": "v9"})
+	require.EqualError(t, err, `milestone "v9" does not exist in the repository jippi/scm-engine`)
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+)
+
+// Ensure the GitHub client implements the [scm.Commenter]
+var _ scm.Commenter = (*Client)(nil)
+
+// BotUsername returns the login of the API token's user
+//
+// The GraphQL viewer is used as the REST 'GET /user' endpoint isn't available to
+// GitHub Actions and GitHub App installation tokens
+func (c *Client) BotUsername(ctx context.Context) (string, error) {
+	if len(c.botUsername) > 0 {
+		return c.botUsername, nil
+	}
+
+	var query struct {
+		Viewer struct {
+			Login string `graphql:"login"`
+		} `graphql:"viewer"`
+	}
+
+	if err := c.newGraphQLClient(ctx).Query(ctx, &query, nil); err != nil {
+		return "", fmt.Errorf("could not read the current user: %w", err)
+	}
+
+	c.botUsername = query.Viewer.Login
+
+	return c.botUsername, nil
+}
+
+// CreateComment adds a comment to the conversation of the Pull Request, which GitHub models as an issue comment
+func (c *Client) CreateComment(ctx context.Context, body string) error {
+	owner, repo := ownerAndRepo(ctx)
+
+	_, _, err := c.wrapped.Issues.CreateComment(ctx, owner, repo, state.MergeRequestIDInt(ctx), &go_github.IssueComment{
+		Body: scm.Ptr(body),
+	})
+
+	return err
+}
+
+func (c *Client) DeleteComment(ctx context.Context, id int64) error {
+	owner, repo := ownerAndRepo(ctx)
+
+	_, err := c.wrapped.Issues.DeleteComment(ctx, owner, repo, id)
+
+	return err
+}
+
+func (c *Client) ListComments(ctx context.Context) ([]scm.Comment, error) {
+	owner, repo := ownerAndRepo(ctx)
+
+	var (
+		comments []scm.Comment
+		opts     = &go_github.IssueListCommentsOptions{
+			ListOptions: go_github.ListOptions{PerPage: 100},
+		}
+	)
+
+	for {
+		page, resp, err := c.wrapped.Issues.ListComments(ctx, owner, repo, state.MergeRequestIDInt(ctx), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, comment := range page {
+			comments = append(comments, scm.Comment{ID: comment.GetID(), Body: comment.GetBody(), Author: comment.GetUser().GetLogin()})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return comments, nil
+}
+
+func (c *Client) UpdateComment(ctx context.Context, id int64, body string) error {
+	owner, repo := ownerAndRepo(ctx)
+
+	_, _, err := c.wrapped.Issues.EditComment(ctx, owner, repo, id, &go_github.IssueComment{
+		Body: scm.Ptr(body),
+	})
+
+	return err
+}
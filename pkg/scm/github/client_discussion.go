@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"errors"
+
+	go_github "github.com/google/go-github/v90/github"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+)
+
+// Ensure the GitHub client implements the [scm.Discussioner]
+var _ scm.Discussioner = (*Client)(nil)
+
+// CreateDiscussion starts a review thread on the Pull Request.
+//
+// GitHub review comments are always anchored to a file, on the line when provided
+func (c *Client) CreateDiscussion(ctx context.Context, body string, position *scm.DiscussionPosition) error {
+	if position == nil {
+		return errors.New("GitHub review threads must be anchored to a file, set the 'file' step field")
+	}
+
+	owner, repo := ownerAndRepo(ctx)
+
+	comment := &go_github.PullRequestComment{
+		Body:        scm.Ptr(body),
+		CommitID:    scm.Ptr(state.CommitSHA(ctx)),
+		Path:        scm.Ptr(position.Path),
+		SubjectType: scm.Ptr("file"),
+	}
+
+	if position.Line > 0 {
+		comment.SubjectType = scm.Ptr("line")
+		comment.Line = scm.Ptr(position.Line)
+		comment.Side = scm.Ptr("RIGHT")
+	}
+
+	_, _, err := c.wrapped.PullRequests.CreateComment(ctx, owner, repo, state.MergeRequestIDInt(ctx), comment)
+
+	return err
+}
+
+// ListDiscussions returns the review threads on the Pull Request
+func (c *Client) ListDiscussions(ctx context.Context) ([]scm.Discussion, error) {
+	owner, repo := ownerAndRepo(ctx)
+
+	var (
+		discussions []scm.Discussion
+		variables   = map[string]any{
+			"owner":  owner,
+			"repo":   repo,
+			"pr":     state.MergeRequestIDInt(ctx),
+			"cursor": (*string)(nil),
+		}
+	)
+
+	for {
+		var query struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							EndCursor   *string `graphql:"endCursor"`
+							HasNextPage bool    `graphql:"hasNextPage"`
+						} `graphql:"pageInfo"`
+						Nodes []struct {
+							ID         string `graphql:"id"`
+							IsResolved bool   `graphql:"isResolved"`
+							Comments   struct {
+								Nodes []struct {
+									Body   string `graphql:"body"`
+									Author struct {
+										Login string `graphql:"login"`
+									} `graphql:"author"`
+								} `graphql:"nodes"`
+							} `graphql:"comments(first:1)"`
+						} `graphql:"nodes"`
+					} `graphql:"reviewThreads(first:100, after: $cursor)"`
+				} `graphql:"pullRequest(number: $pr)"`
+			} `graphql:"repository(owner: $owner, name: $repo)"`
+		}
+
+		if err := c.newGraphQLClient(ctx).Query(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+
+		threads := query.Repository.PullRequest.ReviewThreads
+
+		for _, thread := range threads.Nodes {
+			discussion := scm.Discussion{ID: thread.ID, Resolved: thread.IsResolved}
+
+			if len(thread.Comments.Nodes) > 0 {
+				discussion.Body = thread.Comments.Nodes[0].Body
+				discussion.Author = thread.Comments.Nodes[0].Author.Login
+			}
+
+			discussions = append(discussions, discussion)
+		}
+
+		if !threads.PageInfo.HasNextPage {
+			break
+		}
+
+		variables["cursor"] = threads.PageInfo.EndCursor
+	}
+
+	return discussions, nil
+}
+
+func (c *Client) ResolveDiscussion(ctx context.Context, id string) error {
+	var mutation struct {
+		ResolveReviewThread struct {
+			ClientMutationID *string `graphql:"clientMutationId"`
+		} `graphql:"resolveReviewThread(input: $input)"`
+	}
+
+	return c.newGraphQLClient(ctx).Mutate(ctx, &mutation, map[string]any{
+		"input": ResolveReviewThreadInput{ThreadID: id},
+	})
+}
+
+// ResolveReviewThreadInput is the input of the 'resolveReviewThread' GraphQL mutation
+type ResolveReviewThreadInput struct {
+	ThreadID string `json:"threadId"`
+}
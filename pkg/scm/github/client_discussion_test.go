@@ -0,0 +1,163 @@
+package github_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_discussions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		comments []map[string]any
+		queries  []string
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/jippi/scm-engine/pulls/42/comments":
+			var body map[string]any
+
+			json.NewDecoder(r.Body).Decode(&body)
+
+			comments = append(comments, body)
+
+			fmt.Fprint(w, `{"id": 1}`)
+
+		case "POST /graphql":
+			var request struct {
+				Query     string         `json:"query"`
+				Variables map[string]any `json:"variables"`
+			}
+
+			json.NewDecoder(r.Body).Decode(&request)
+
+			queries = append(queries, request.Query)
+
+			if strings.HasPrefix(request.Query, "mutation") {
+				fmt.Fprint(w, `{"data": {"resolveReviewThread": {"clientMutationId": null}}}`)
+
+				return
+			}
+
+			// The threads are split over two pages
+			if request.Variables["cursor"] == nil {
+				fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+					"pageInfo": {"endCursor": "page-2", "hasNextPage": true},
+					"nodes": [{"id": "PRRT_1", "isResolved": false, "comments": {"nodes": [{"body": "Please fix", "author": {"login": "scm-engine"}}]}}]
+				}}}}}`)
+
+				return
+			}
+
+			fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"endCursor": null, "hasNextPage": false},
+				"nodes": [{"id": "PRRT_2", "isResolved": true, "comments": {"nodes": []}}]
+			}}}}}`)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+	ctx = state.WithCommitSHA(ctx, "c0ffee")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	// Review threads must be anchored to a file
+	require.EqualError(t, client.CreateDiscussion(ctx, "Hello", nil), "GitHub review threads must be anchored to a file, set the 'file' step field")
+
+	require.NoError(t, client.CreateDiscussion(ctx, "Hello", &scm.DiscussionPosition{Path: "go.mod"}))
+	require.NoError(t, client.CreateDiscussion(ctx, "Hello", &scm.DiscussionPosition{Path: "main.go", Line: 3}))
+
+	require.Equal(t, []map[string]any{
+		{"body": "Hello", "commit_id": "c0ffee", "path": "go.mod", "subject_type": "file"},
+		{"body": "Hello", "commit_id": "c0ffee", "path": "main.go", "subject_type": "line", "line": float64(3), "side": "RIGHT"},
+	}, comments)
+
+	discussions, err := client.ListDiscussions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []scm.Discussion{
+		{ID: "PRRT_1", Body: "Please fix", Author: "scm-engine"},
+		{ID: "PRRT_2", Resolved: true},
+	}, discussions)
+
+	require.NoError(t, client.ResolveDiscussion(ctx, "PRRT_1"))
+	require.Len(t, queries, 3)
+	require.Contains(t, queries[2], "resolveReviewThread(input: $input)")
+}
+
+// Threads started with a GitHub Actions or GitHub App token are found through the GraphQL viewer,
+// which uses the same login format as the thread authors
+func TestClient_discussionsByApp(t *testing.T) {
+	t.Parallel()
+
+	var resolved []any
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			http.Error(w, "Resource not accessible by integration", http.StatusForbidden)
+
+			return
+		}
+
+		var request struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasPrefix(request.Query, "mutation"):
+			resolved = append(resolved, request.Variables["input"])
+
+			fmt.Fprint(w, `{"data": {"resolveReviewThread": {"clientMutationId": null}}}`)
+
+		case strings.Contains(request.Query, "viewer"):
+			fmt.Fprint(w, `{"data": {"viewer": {"login": "github-actions"}}}`)
+
+		default:
+			fmt.Fprintf(w, `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"pageInfo": {"endCursor": null, "hasNextPage": false},
+				"nodes": [
+					{"id": "PRRT_1", "isResolved": false, "comments": {"nodes": [{"body": %[1]q, "author": {"login": "github-actions"}}]}},
+					{"id": "PRRT_2", "isResolved": false, "comments": {"nodes": [{"body": %[1]q, "author": {"login": "jippi"}}]}}
+				]
+			}}}}}`, "Too big\n\n"+config.DiscussionMarker("too-big"))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+	ctx = state.WithDryRun(ctx, false)
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, config.ResolveDiscussions(ctx, config.ActionStep{"action": "resolve_discussions", "key": "too-big"}, client))
+	require.Equal(t, []any{map[string]any{"threadId": "PRRT_1"}}, resolved)
+}
@@ -39,3 +39,14 @@ func ownerAndRepo(ctx context.Context) (string, string) {
 
 	return chunks[0], chunks[1]
 }
+
+// graphqlBaseURL returns the GraphQL endpoint matching a REST API base URL.
+//
+// GitHub.com serves both from the same host (https://api.github.com/graphql), while
+// GitHub Enterprise Server serves REST from /api/v3/ and GraphQL from /api/graphql
+func graphqlBaseURL(restBaseURL string) string {
+	base := strings.TrimSuffix(restBaseURL, "/")
+	base = strings.TrimSuffix(base, "/v3")
+
+	return base + "/graphql"
+}
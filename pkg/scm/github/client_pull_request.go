@@ -2,12 +2,17 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	go_github "github.com/google/go-github/v90/github"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
 )
 
 var _ scm.MergeRequestClient = (*MergeRequestClient)(nil)
@@ -24,8 +29,10 @@ func (client *MergeRequestClient) Update(ctx context.Context, opt *scm.UpdateMer
 	owner, repo := ownerAndRepo(ctx)
 
 	// Add labels
-	if _, resp, err := client.client.wrapped.Issues.AddLabelsToIssue(ctx, owner, repo, state.MergeRequestIDInt(ctx), *opt.AddLabels); err != nil {
-		return convertResponse(resp), err
+	if opt.AddLabels != nil && len(*opt.AddLabels) > 0 {
+		if _, resp, err := client.client.wrapped.Issues.AddLabelsToIssue(ctx, owner, repo, state.MergeRequestIDInt(ctx), *opt.AddLabels); err != nil {
+			return convertResponse(resp), err
+		}
 	}
 
 	// Remove labels
@@ -45,18 +52,159 @@ func (client *MergeRequestClient) Update(ctx context.Context, opt *scm.UpdateMer
 
 	// Update MR
 	updatePullRequest := &go_github.PullRequest{
+		Title:  opt.Title,
+		Body:   opt.Description,
 		Locked: opt.DiscussionLocked,
 	}
 
-	_, resp, err := client.client.wrapped.PullRequests.Edit(ctx, owner, repo, state.MergeRequestIDInt(ctx), updatePullRequest)
+	pullRequest, resp, err := client.client.wrapped.PullRequests.Edit(ctx, owner, repo, state.MergeRequestIDInt(ctx), updatePullRequest)
+	if err != nil {
+		return convertResponse(resp), err
+	}
+
+	// Milestone and assignees belong to the issue of the Pull Request
+	if opt.MilestoneID != nil || opt.Assignees != nil {
+		updateIssue := go_github.UpdateIssueRequest{
+			Milestone: opt.MilestoneID,
+		}
+
+		if opt.Assignees != nil {
+			updateIssue.Assignees = *opt.Assignees
+		}
 
-	return convertResponse(resp), err
+		_, resp, err = client.client.wrapped.Issues.Update(ctx, owner, repo, state.MergeRequestIDInt(ctx), updateIssue)
+		if err != nil {
+			return convertResponse(resp), err
+		}
+	}
+
+	// Draft status can only be changed through the GraphQL API
+	if opt.Draft != nil {
+		if err := client.setDraft(ctx, pullRequest.GetNodeID(), *opt.Draft); err != nil {
+			return convertResponse(resp), fmt.Errorf("could not change the draft status of the Pull Request: %w", err)
+		}
+	}
+
+	// Update the branch with the base branch
+	if opt.Rebase != nil {
+		if opt.Rebase.SkipCI {
+			slogctx.Warn(ctx, "GitHub does not support skipping CI when updating the Pull Request branch, ignoring 'skip_ci'")
+		}
+
+		_, resp, err = client.client.wrapped.PullRequests.UpdateBranch(ctx, owner, repo, state.MergeRequestIDInt(ctx), nil)
+
+		// GitHub responds with '202 Accepted' as the update happens in the background
+		var accepted *go_github.AcceptedError
+		if errors.As(err, &accepted) {
+			err = nil
+		}
+
+		if err != nil {
+			return convertResponse(resp), fmt.Errorf("could not update Pull Request branch: %w", err)
+		}
+	}
+
+	return convertResponse(resp), nil
 }
 
 func (client *MergeRequestClient) GetRemoteConfig(ctx context.Context, filename, ref string) (io.Reader, error) {
-	return nil, nil //nolint:nilnil
+	owner, repo := ownerAndRepo(ctx)
+
+	options := &go_github.RepositoryContentGetOptions{Ref: ref}
+
+	switch ref {
+	// GitHub reads from the default branch when no ref is provided
+	case "HEAD":
+		options.Ref = ""
+
+	// Events without a commit SHA (e.g. comments) read from the Pull Request head
+	case "":
+		options.Ref = fmt.Sprintf("refs/pull/%d/head", state.MergeRequestIDInt(ctx))
+	}
+
+	file, _, _, err := client.client.wrapped.Repositories.GetContents(ctx, owner, repo, filename, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if file == nil {
+		return nil, fmt.Errorf("%q is a directory, expected a file", filename)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(content), nil
 }
 
 func (client *MergeRequestClient) List(ctx context.Context, options *scm.ListMergeRequestsOptions) ([]scm.ListMergeRequest, error) {
-	return nil, nil //nolint:nilnil
+	owner, repo := ownerAndRepo(ctx)
+
+	listOptions := &go_github.PullRequestListOptions{
+		State: pullRequestState(options.State),
+		ListOptions: go_github.ListOptions{
+			PerPage: options.First,
+		},
+	}
+
+	if len(options.SourceBranch) > 0 {
+		listOptions.Head = owner + ":" + options.SourceBranch
+	}
+
+	pullRequests, _, err := client.client.wrapped.PullRequests.List(ctx, owner, repo, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []scm.ListMergeRequest{}
+
+	for _, pullRequest := range pullRequests {
+		results = append(results, scm.ListMergeRequest{
+			ID:  strconv.Itoa(pullRequest.GetNumber()),
+			SHA: pullRequest.GetHead().GetSHA(),
+		})
+	}
+
+	return results, nil
+}
+
+func (client *MergeRequestClient) setDraft(ctx context.Context, pullRequestID string, draft bool) error {
+	if draft {
+		var mutation struct {
+			ConvertPullRequestToDraft struct {
+				ClientMutationID *string `graphql:"clientMutationId"`
+			} `graphql:"convertPullRequestToDraft(input: $input)"`
+		}
+
+		return client.client.newGraphQLClient(ctx).Mutate(ctx, &mutation, map[string]any{
+			"input": ConvertPullRequestToDraftInput{PullRequestID: pullRequestID},
+		})
+	}
+
+	var mutation struct {
+		MarkPullRequestReadyForReview struct {
+			ClientMutationID *string `graphql:"clientMutationId"`
+		} `graphql:"markPullRequestReadyForReview(input: $input)"`
+	}
+
+	return client.client.newGraphQLClient(ctx).Mutate(ctx, &mutation, map[string]any{
+		"input": MarkPullRequestReadyForReviewInput{PullRequestID: pullRequestID},
+	})
+}
+
+// pullRequestState maps the GitLab flavored Merge Request states used throughout
+// scm-engine to the Pull Request states understood by GitHub
+func pullRequestState(in string) string {
+	switch in {
+	case "", "opened":
+		return "open"
+
+	case "closed", "merged":
+		return "closed"
+
+	default:
+		return in
+	}
 }
@@ -0,0 +1,466 @@
+package github_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+const periodicEvaluationRepositoriesResponse = `{"data": {"viewer": {"repositories": {"nodes": [
+  {
+    "nameWithOwner": "jippi/scm-engine",
+    "repositoryTopics": {"nodes": [{"topic": {"name": "scm-engine"}}, {"topic": {"name": "go"}}]},
+    "object": {"text": "label: []"}
+  },
+  {
+    "nameWithOwner": "jippi/no-config",
+    "repositoryTopics": {"nodes": [{"topic": {"name": "scm-engine"}}]},
+    "object": null
+  },
+  {
+    "nameWithOwner": "jippi/no-topic",
+    "repositoryTopics": {"nodes": []},
+    "object": {"text": "label: []"}
+  }
+]}}}}`
+
+// periodicEvaluationPullRequestsResponse maps the repository name to its open Pull Requests
+var periodicEvaluationPullRequestsResponse = map[string]string{
+	"scm-engine": `[
+      {"number": 1, "headRefOid": "sha-1", "labels": {"nodes": []}},
+      {"number": 2, "headRefOid": "sha-2", "labels": {"nodes": [{"name": "do-not-close"}]}},
+      {"number": 3, "headRefOid": "sha-3", "labels": {"nodes": [{"name": "stale"}]}}
+    ]`,
+	"no-config": `[{"number": 4, "headRefOid": "sha-4", "labels": {"nodes": [{"name": "stale"}]}}]`,
+	"no-topic":  `[{"number": 5, "headRefOid": "sha-5", "labels": {"nodes": []}}]`,
+}
+
+func TestClient_FindMergeRequestsForPeriodicEvaluation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filters scm.MergeRequestListFilters
+		want    []scm.PeriodicEvaluationMergeRequest
+	}{
+		{
+			name:    "without filters",
+			filters: scm.MergeRequestListFilters{},
+			want: []scm.PeriodicEvaluationMergeRequest{
+				{Project: "jippi/scm-engine", MergeRequestID: "1", SHA: "sha-1", ConfigBlob: "label: []"},
+				{Project: "jippi/scm-engine", MergeRequestID: "2", SHA: "sha-2", ConfigBlob: "label: []"},
+				{Project: "jippi/scm-engine", MergeRequestID: "3", SHA: "sha-3", ConfigBlob: "label: []"},
+				{Project: "jippi/no-config", MergeRequestID: "4", SHA: "sha-4"},
+				{Project: "jippi/no-topic", MergeRequestID: "5", SHA: "sha-5", ConfigBlob: "label: []"},
+			},
+		},
+		{
+			name:    "only repositories with all topics",
+			filters: scm.MergeRequestListFilters{OnlyProjectsWithTopics: []string{"scm-engine", "go"}},
+			want: []scm.PeriodicEvaluationMergeRequest{
+				{Project: "jippi/scm-engine", MergeRequestID: "1", SHA: "sha-1", ConfigBlob: "label: []"},
+				{Project: "jippi/scm-engine", MergeRequestID: "2", SHA: "sha-2", ConfigBlob: "label: []"},
+				{Project: "jippi/scm-engine", MergeRequestID: "3", SHA: "sha-3", ConfigBlob: "label: []"},
+			},
+		},
+		{
+			name: "required and ignored labels",
+			filters: scm.MergeRequestListFilters{
+				OnlyProjectsWithTopics:       []string{"scm-engine"},
+				OnlyMergeRequestsWithLabels:  []string{"stale"},
+				IgnoreMergeRequestWithLabels: []string{"do-not-close"},
+			},
+			want: []scm.PeriodicEvaluationMergeRequest{
+				{Project: "jippi/scm-engine", MergeRequestID: "3", SHA: "sha-3", ConfigBlob: "label: []"},
+				{Project: "jippi/no-config", MergeRequestID: "4", SHA: "sha-4"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []map[string]any
+
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request struct {
+					Variables map[string]any `json:"variables"`
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+
+					return
+				}
+
+				requests = append(requests, request.Variables)
+
+				w.Header().Set("Content-Type", "application/json")
+
+				repo, ok := request.Variables["repo"].(string)
+				if !ok {
+					w.Write([]byte(periodicEvaluationRepositoriesResponse))
+
+					return
+				}
+
+				fmt.Fprintf(w, `{"data": {"repository": {"pullRequests": {"nodes": %s}}}}`, periodicEvaluationPullRequestsResponse[repo])
+			}))
+			t.Cleanup(upstream.Close)
+
+			ctx := state.WithToken(t.Context(), "token")
+			ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+
+			client, err := github.NewClient(ctx, nil, nil)
+			require.NoError(t, err)
+
+			got, err := client.FindMergeRequestsForPeriodicEvaluation(ctx, tt.filters)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			// The config file is read from the default branch in the same query as the repositories
+			require.Equal(t, map[string]any{"config_expression": "HEAD:.scm-engine.yml", "repositories_cursor": nil}, requests[0])
+		})
+	}
+}
+
+func TestClient_FindMergeRequestsForPeriodicEvaluation_pagination(t *testing.T) {
+	t.Parallel()
+
+	var requests []map[string]any
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]any `json:"variables"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		requests = append(requests, request.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		// The first page of Pull Requests in the first repository
+		case request.Variables["repo"] == "first" && request.Variables["cursor"] == nil:
+			w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+  "pageInfo": {"endCursor": "pull-requests-1", "hasNextPage": true},
+  "nodes": [{"number": 1, "headRefOid": "sha-1", "labels": {"nodes": []}}]
+}}}}`))
+
+		// The next page of Pull Requests in the first repository
+		case request.Variables["repo"] == "first":
+			w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+  "pageInfo": {"endCursor": "pull-requests-2", "hasNextPage": false},
+  "nodes": [{"number": 2, "headRefOid": "sha-2", "labels": {"nodes": []}}]
+}}}}`))
+
+		case request.Variables["repo"] == "second":
+			w.Write([]byte(`{"data": {"repository": {"pullRequests": {
+  "pageInfo": {"hasNextPage": false},
+  "nodes": [{"number": 3, "headRefOid": "sha-3", "labels": {"nodes": []}}]
+}}}}`))
+
+		// The next page of repositories
+		case request.Variables["repositories_cursor"] == "repositories-1":
+			w.Write([]byte(`{"data": {"viewer": {"repositories": {
+  "pageInfo": {"endCursor": "repositories-2", "hasNextPage": false},
+  "nodes": [{"nameWithOwner": "jippi/second", "repositoryTopics": {"nodes": []}, "object": null}]
+}}}}`))
+
+		default:
+			w.Write([]byte(`{"data": {"viewer": {"repositories": {
+  "pageInfo": {"endCursor": "repositories-1", "hasNextPage": true},
+  "nodes": [{"nameWithOwner": "jippi/first", "repositoryTopics": {"nodes": []}, "object": null}]
+}}}}`))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	got, err := client.FindMergeRequestsForPeriodicEvaluation(ctx, scm.MergeRequestListFilters{})
+	require.NoError(t, err)
+	require.Equal(t, []scm.PeriodicEvaluationMergeRequest{
+		{Project: "jippi/first", MergeRequestID: "1", SHA: "sha-1"},
+		{Project: "jippi/first", MergeRequestID: "2", SHA: "sha-2"},
+		{Project: "jippi/second", MergeRequestID: "3", SHA: "sha-3"},
+	}, got)
+
+	require.Equal(t, []map[string]any{
+		{"config_expression": "HEAD:.scm-engine.yml", "repositories_cursor": nil},
+		{"owner": "jippi", "repo": "first", "cursor": nil},
+		{"owner": "jippi", "repo": "first", "cursor": "pull-requests-1"},
+		{"config_expression": "HEAD:.scm-engine.yml", "repositories_cursor": "repositories-1"},
+		{"owner": "jippi", "repo": "second", "cursor": nil},
+	}, requests)
+}
+
+// GitHub rejects queries that could return more than 500,000 nodes
+func TestPeriodicEvaluation_nodeLimit(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 10_100, graphqlNodeCost(reflect.TypeFor[github.PeriodicEvaluationResult](), 1))
+	require.Equal(t, 10_100, graphqlNodeCost(reflect.TypeFor[github.PeriodicEvaluationPullRequestsResult](), 1))
+}
+
+var graphqlFirstRegex = regexp.MustCompile(`\bfirst:\s*(\d+)`)
+
+// graphqlNodeCost computes the maximum number of nodes the query could return from the
+// 'first' argument of the connections in the graphql struct tags, the way GitHub does it
+func graphqlNodeCost(typ reflect.Type, parents int) int {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return 0
+	}
+
+	cost := 0
+
+	for field := range typ.Fields() {
+		nodes := parents
+
+		if match := graphqlFirstRegex.FindStringSubmatch(field.Tag.Get("graphql")); match != nil {
+			first, _ := strconv.Atoi(match[1])
+
+			nodes *= first
+			cost += nodes
+		}
+
+		cost += graphqlNodeCost(field.Type, nodes)
+	}
+
+	return cost
+}
+
+func TestClient_GetProjectFiles(t *testing.T) {
+	t.Parallel()
+
+	var refs []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refs = append(refs, r.URL.Query().Get("ref"))
+
+		switch r.URL.Path {
+		case "/repos/jippi/shared/contents/labels.yml":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type": "file", "encoding": "base64", "content": "bGFiZWw6IFtd"}`))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	ref := "v1"
+
+	got, err := client.GetProjectFiles(ctx, "jippi/shared", &ref, []string{"labels.yml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"labels.yml": "label: []"}, got)
+
+	// HEAD reads from the default branch
+	head := "HEAD"
+
+	_, err = client.GetProjectFiles(ctx, "jippi/shared", &head, []string{"missing.yml"})
+	require.ErrorContains(t, err, "configuration file [missing.yml] in project [jippi/shared] does not exist")
+
+	require.Equal(t, []string{"v1", ""}, refs)
+
+	_, err = client.GetProjectFiles(ctx, "shared", nil, []string{"labels.yml"})
+	require.EqualError(t, err, "project [shared] must be in 'owner/repo' format")
+}
+
+// The login is read from the GraphQL viewer, as 'GET /user' is forbidden for GitHub Actions and App tokens
+func TestClient_BotUsername(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/graphql" {
+			http.Error(w, "Resource not accessible by integration", http.StatusForbidden)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data": {"viewer": {"login": "github-actions"}}}`)
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	for range 2 {
+		got, err := client.BotUsername(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "github-actions", got)
+	}
+
+	// The login is only read once
+	require.Equal(t, 1, requests)
+}
+
+func TestClient_GetProjectFileSHAs(t *testing.T) {
+	t.Parallel()
+
+	var expressions []any
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]any `json:"variables"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		expressions = append(expressions, request.Variables["expression"])
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if request.Variables["expression"] == "v1:labels.yml" {
+			fmt.Fprint(w, `{"data": {"repository": {"object": {"oid": "0d5cb0cebb666913935559eae2a90b08aa62cd66"}}}}`)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"data": {"repository": {"object": null}}}`)
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	ref := "v1"
+
+	// Files that don't exist are left out
+	got, err := client.GetProjectFileSHAs(ctx, "jippi/shared", &ref, []string{"labels.yml", "missing.yml"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"labels.yml": "0d5cb0cebb666913935559eae2a90b08aa62cd66"}, got)
+
+	// Without a ref the default branch is used
+	_, err = client.GetProjectFileSHAs(ctx, "jippi/shared", nil, []string{"labels.yml"})
+	require.NoError(t, err)
+
+	require.Equal(t, []any{"v1:labels.yml", "v1:missing.yml", "HEAD:labels.yml"}, expressions)
+}
+
+func TestMergeRequestClient_Update_rebase(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repos/jippi/scm-engine/pulls/42/update-branch":
+			// The branch is updated in the background
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"message": "Updating pull request branch."}`))
+
+		default:
+			w.Write([]byte(`{"number": 42}`))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.MergeRequests().Update(ctx, &scm.UpdateMergeRequestOptions{Rebase: &scm.RebaseOptions{SkipCI: true}})
+	require.NoError(t, err)
+
+	// The branch is updated after the Pull Request itself
+	require.Equal(t, []string{
+		"PATCH /repos/jippi/scm-engine/pulls/42",
+		"PUT /repos/jippi/scm-engine/pulls/42/update-branch",
+	}, requests)
+}
+
+func TestMergeRequestClient_Update_titleMilestoneAssigneesAndDraft(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(body)))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/graphql":
+			w.Write([]byte(`{"data": {"convertPullRequestToDraft": {"clientMutationId": null}}}`))
+
+		default:
+			w.Write([]byte(`{"number": 42, "node_id": "PR_42"}`))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, upstream.URL+"/")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	client, err := github.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.MergeRequests().Update(ctx, &scm.UpdateMergeRequestOptions{
+		Title:       scm.Ptr("Fresh"),
+		MilestoneID: scm.Ptr(3),
+		Assignees:   &[]string{"jippi"},
+		Draft:       scm.Ptr(true),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	require.Equal(t, `PATCH /repos/jippi/scm-engine/pulls/42 {"title":"Fresh"}`, requests[0])
+	require.Equal(t, `PATCH /repos/jippi/scm-engine/issues/42 {"milestone":3,"assignees":["jippi"]}`, requests[1])
+	require.Contains(t, requests[2], "convertPullRequestToDraft(input: $input)")
+	require.Contains(t, requests[2], `"pullRequestId":"PR_42"`)
+}
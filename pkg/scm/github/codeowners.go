@@ -0,0 +1,77 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+type codeOwnersRule struct {
+	pattern string
+	owners  []string
+}
+
+// parseCodeOwners parses the content of a CODEOWNERS file into a list of rules,
+// skipping lines with an invalid pattern just like GitHub does it
+//
+// See: https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+func parseCodeOwners(content string) []codeOwnersRule {
+	var rules []codeOwnersRule
+
+	for line := range strings.Lines(content) {
+		// Strip comments
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if scm.ValidatePattern(fields[0]) != nil {
+			continue
+		}
+
+		rules = append(rules, codeOwnersRule{pattern: fields[0], owners: fields[1:]})
+	}
+
+	return rules
+}
+
+// codeOwnersFor returns the owners of the files, where the last matching rule
+// for a file takes precedence, just like GitHub does it
+func codeOwnersFor(rules []codeOwnersRule, files []string) scm.Actors {
+	actors := make(scm.Actors, 0)
+
+	for _, file := range files {
+		var owners []string
+
+		for _, rule := range rules {
+			if len(scm.FindModifiedFiles([]string{file}, rule.pattern)) > 0 {
+				owners = rule.owners
+			}
+		}
+
+		for _, owner := range owners {
+			// Owners identified by email can't be mapped to a GitHub user
+			login, ok := strings.CutPrefix(owner, "@")
+			if !ok {
+				continue
+			}
+
+			actor := scm.Actor{
+				Username: login,
+				IsTeam:   strings.Contains(login, "/"),
+			}
+
+			if actors.Has(actor) {
+				continue
+			}
+
+			actors.Add(actor)
+		}
+	}
+
+	return actors
+}
@@ -2,6 +2,10 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/hasura/go-graphql-client"
@@ -12,7 +16,7 @@ import (
 
 var _ scm.EvalContext = (*Context)(nil)
 
-func NewContext(ctx context.Context, _, token string) (*Context, error) {
+func NewContext(ctx context.Context, baseURL, token string) (*Context, error) {
 	httpClient := oauth2.NewClient(
 		ctx,
 		oauth2.StaticTokenSource(
@@ -24,7 +28,7 @@ func NewContext(ctx context.Context, _, token string) (*Context, error) {
 
 	owner, repo := ownerAndRepo(ctx)
 
-	client := graphql.NewClient("https://api.github.com/graphql", httpClient)
+	client := graphql.NewClient(baseURL, httpClient)
 
 	var (
 		evalContext *Context
@@ -32,6 +36,11 @@ func NewContext(ctx context.Context, _, token string) (*Context, error) {
 			"owner": owner,
 			"repo":  repo,
 			"pr":    state.MergeRequestIDInt(ctx),
+
+			// CODEOWNERS locations, read from the default branch
+			"codeowners_github": "HEAD:.github/CODEOWNERS",
+			"codeowners_root":   "HEAD:CODEOWNERS",
+			"codeowners_docs":   "HEAD:docs/CODEOWNERS",
 		}
 	)
 
@@ -80,6 +89,25 @@ func NewContext(ctx context.Context, _, token string) (*Context, error) {
 	return evalContext, nil
 }
 
+// LoadContext reads an evaluation context snapshot written by --dump-context, so a configuration
+// can be evaluated without any network access
+func LoadContext(r io.Reader) (*Context, error) {
+	var evalContext *Context
+
+	if err := json.NewDecoder(r).Decode(&evalContext); err != nil {
+		return nil, fmt.Errorf("could not decode context snapshot: %w", err)
+	}
+
+	if evalContext == nil || evalContext.PullRequest == nil {
+		return nil, errors.New("the context snapshot does not contain a pull request")
+	}
+
+	// Initialize null-able types
+	evalContext.ActionGroups = make(map[string]any)
+
+	return evalContext, nil
+}
+
 func (c *Context) IsValid() bool {
 	return c != nil
 }
@@ -96,6 +124,10 @@ func (c *Context) GetDescription() string {
 	return c.PullRequest.Body
 }
 
+func (c *Context) GetTitle() string {
+	return c.PullRequest.Title
+}
+
 func (c *Context) CanUseConfigurationFileFromChangeRequest(ctx context.Context) bool {
 	return true
 }
@@ -126,19 +158,92 @@ func (c *Context) AllowPipelineFailure(ctx context.Context) bool {
 	return len(c.PullRequest.findModifiedFiles(state.ConfigFilePath(ctx))) == 1
 }
 
+// GetCodeOwners returns the code owners for the files changed in the pull request
+//
+// This is based on the CODEOWNERS file in the default branch of the repository. Teams
+// are returned as-is, and the author is never considered a code owner of their own pull request.
 func (c *Context) GetCodeOwners() scm.Actors {
-	// unimplemented
-	return make(scm.Actors, 0)
+	actors := make(scm.Actors, 0)
+
+	var content string
+
+	// GitHub uses the first CODEOWNERS file found, in this order
+	for _, blob := range []*ContextBlob{c.Repository.ResponseCodeOwnersGitHub, c.Repository.ResponseCodeOwnersRoot, c.Repository.ResponseCodeOwnersDocs} {
+		if blob != nil && blob.Blob != nil && blob.Blob.Text != nil {
+			content = *blob.Blob.Text
+
+			break
+		}
+	}
+
+	if len(content) == 0 {
+		return actors
+	}
+
+	files := make([]string, 0, len(c.PullRequest.Files))
+	for _, file := range c.PullRequest.Files {
+		files = append(files, file.Path)
+	}
+
+	author := c.GetAuthor()
+
+	for _, actor := range codeOwnersFor(parseCodeOwners(content), files) {
+		if actor.Username == author.Username {
+			continue
+		}
+
+		actors.Add(actor)
+	}
+
+	return actors
 }
 
+// GetReviewers returns the users and teams requested to review the pull request,
+// and the users who already reviewed it (GitHub removes them from the requested reviewers)
 func (c *Context) GetReviewers() scm.Actors {
-	// unimplemented
-	return make(scm.Actors, 0)
+	actors := make(scm.Actors, 0)
+
+	if c.PullRequest.ResponseReviewRequests != nil {
+		for _, request := range c.PullRequest.ResponseReviewRequests.Nodes {
+			actor := request.RequestedReviewer.ToActor()
+			if len(actor.Username) == 0 || actors.Has(actor) {
+				continue
+			}
+
+			actors.Add(actor)
+		}
+	}
+
+	if c.PullRequest.ResponseLatestReviews != nil {
+		for _, review := range c.PullRequest.ResponseLatestReviews.Nodes {
+			actor := review.Author.ToActor()
+			if len(actor.Username) == 0 || actors.Has(actor) {
+				continue
+			}
+
+			actors.Add(actor)
+		}
+	}
+
+	return actors
+}
+
+func (c *Context) GetAssignees() scm.Actors {
+	actors := make(scm.Actors, 0)
+
+	if c.PullRequest.ResponseAssignees == nil {
+		return actors
+	}
+
+	for _, assignee := range c.PullRequest.ResponseAssignees.Nodes {
+		actors.Add(assignee.ToActor())
+	}
+
+	return actors
 }
 
 func (c *Context) GetAuthor() scm.Actor {
-	// unimplemented
-	return scm.Actor{}
+	return c.PullRequest.ResponseAuthor.ToActor()
 }
 
 func (c *Context) GetLabels() []string {
@@ -150,3 +255,13 @@ func (c *Context) GetLabels() []string {
 
 	return labels
 }
+
+func (c *Context) GetModifiedFiles() []string {
+	files := make([]string, 0, len(c.PullRequest.Files))
+
+	for _, file := range c.PullRequest.Files {
+		files = append(files, file.Path)
+	}
+
+	return files
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"strconv"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+)
+
+// ToActor converts a GitHub user, bot or team into an [scm.Actor]
+func (a *ContextActor) ToActor() scm.Actor {
+	if a == nil {
+		return scm.Actor{}
+	}
+
+	switch a.Typename {
+	case "User":
+		if a.User != nil {
+			return scm.Actor{
+				ID:       databaseID(a.User.DatabaseID),
+				Username: a.User.Login,
+			}
+		}
+
+	case "Bot":
+		if a.Bot != nil {
+			return scm.Actor{
+				ID:       databaseID(a.Bot.DatabaseID),
+				Username: a.Bot.Login,
+				IsBot:    true,
+			}
+		}
+
+	case "Team":
+		if a.Team != nil {
+			return scm.Actor{
+				ID:       databaseID(a.Team.DatabaseID),
+				Username: a.Team.CombinedSlug,
+				IsTeam:   true,
+			}
+		}
+	}
+
+	return scm.Actor{}
+}
+
+func (u ContextActorUser) ToActor() scm.Actor {
+	return scm.Actor{
+		ID:       databaseID(u.DatabaseID),
+		Username: u.Login,
+	}
+}
+
+func databaseID(id *int) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*id)
+}
@@ -0,0 +1,47 @@
+package github_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/scm/github"
+	"github.com/stretchr/testify/require"
+)
+
+// A snapshot written by --dump-context must load back into an identical context,
+// otherwise --from-context would evaluate against something GitHub never returned
+func TestLoadContext_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	sinceFirstCommit := 72 * time.Hour
+
+	original := &github.Context{
+		PullRequest: &github.ContextPullRequest{
+			Title:                "Fix the thing",
+			State:                github.PullRequestStateOpen,
+			Mergeable:            github.MergeableStateMergeable,
+			TimeSinceFirstCommit: &sinceFirstCommit,
+		},
+		ActionGroups: map[string]any{},
+	}
+
+	raw, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	loaded, err := github.LoadContext(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.Equal(t, original, loaded)
+}
+
+func TestLoadContext_rejectsInvalidSnapshots(t *testing.T) {
+	t.Parallel()
+
+	_, err := github.LoadContext(strings.NewReader("not json"))
+	require.ErrorContains(t, err, "could not decode context snapshot")
+
+	_, err = github.LoadContext(strings.NewReader("{}"))
+	require.EqualError(t, err, "the context snapshot does not contain a pull request")
+}
@@ -1,6 +1,8 @@
 package github_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/jippi/scm-engine/pkg/scm"
@@ -249,14 +251,93 @@ func TestContext_ActionGroupTracking_ignoresUngrouped(t *testing.T) {
 	require.False(t, evalContext.HasExecutedActionGroup(""))
 }
 
-// These are documented as unimplemented on GitHub; assert the contract so a
-// future implementation has to update the tests deliberately.
-func TestContext_unimplementedAccessors(t *testing.T) {
+// A Pull Request without author, reviewers or CODEOWNERS file must not fail.
+func TestContext_accessorsWithoutData(t *testing.T) {
 	t.Parallel()
 
-	evalContext := &github.Context{}
+	evalContext := &github.Context{
+		Repository:  &github.ContextRepository{},
+		PullRequest: &github.ContextPullRequest{Files: []github.PullRequestChangedFile{{Path: "main.go"}}},
+	}
 
 	require.Empty(t, evalContext.GetCodeOwners())
 	require.Empty(t, evalContext.GetReviewers())
 	require.Equal(t, scm.Actor{}, evalContext.GetAuthor())
 }
+
+const newContextResponse = `{"data": {
+  "user": {"login": "jippi"},
+  "viewer": {"login": "scm-engine"},
+  "repository": {
+    "codeowners_github": null,
+    "codeowners_root": {"text": "# default owners\n* @jippi/core\ndocs/ @author @bob # docs\n*.go @alice someone@example.com\n***.go @mallory\n"},
+    "codeowners_docs": null,
+    "pullRequest": {
+      "number": 42,
+      "author": {"login": "author"},
+      "response_author": {"__typename": "User", "login": "author", "databaseId": 1},
+      "files": {"nodes": [{"path": "docs/index.md"}, {"path": "main.go"}, {"path": "Makefile"}]},
+      "labels": {"nodes": []},
+      "first_commit": {"nodes": []},
+      "last_commit": {"nodes": []},
+      "reviewRequests": {"nodes": [
+        {"requestedReviewer": {"__typename": "User", "login": "alice", "databaseId": 2}},
+        {"requestedReviewer": {"__typename": "Team", "combinedSlug": "jippi/docs", "databaseId": 3}}
+      ]},
+      "latestReviews": {"nodes": [
+        {"author": {"__typename": "Bot", "login": "renovate", "databaseId": 4}},
+        {"author": {"__typename": "User", "login": "alice", "databaseId": 2}}
+      ]}
+    }
+  }
+}}`
+
+func newTestContext(t *testing.T) *github.Context {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(newContextResponse))
+	}))
+	t.Cleanup(upstream.Close)
+
+	ctx := state.WithProjectID(t.Context(), "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	evalContext, err := github.NewContext(ctx, upstream.URL, "token")
+	require.NoError(t, err)
+
+	return evalContext
+}
+
+func TestContext_GetAuthor(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, scm.Actor{ID: "1", Username: "author"}, newTestContext(t).GetAuthor())
+}
+
+func TestContext_GetReviewers(t *testing.T) {
+	t.Parallel()
+
+	want := scm.Actors{
+		{ID: "2", Username: "alice"},
+		{ID: "3", Username: "jippi/docs", IsTeam: true},
+		{ID: "4", Username: "renovate", IsBot: true},
+	}
+
+	require.Equal(t, want, newTestContext(t).GetReviewers())
+}
+
+// The last matching CODEOWNERS rule wins for each file, owners by email and
+// rules with an invalid pattern are skipped, and the author is never a code owner of their own Pull Request.
+func TestContext_GetCodeOwners(t *testing.T) {
+	t.Parallel()
+
+	want := scm.Actors{
+		{Username: "bob"},
+		{Username: "alice"},
+		{Username: "jippi/core", IsTeam: true},
+	}
+
+	require.Equal(t, want, newTestContext(t).GetCodeOwners())
+}
@@ -0,0 +1,173 @@
+package github
+
+import "slices"
+
+// PeriodicEvaluationResult structs maps to the GraphQL query used to find repositories
+// with Pull Requests that should be periodically evaluated.
+//
+// GitHub can't filter repositories by topic or Pull Requests by (the absence of) labels
+// in the query itself, so those filters are applied on the response instead.
+//
+// The open Pull Requests are read per repository with [PeriodicEvaluationPullRequestsResult],
+// nesting them here would exceed the GitHub limit of 500,000 nodes per query.
+//
+// GraphQL query:
+//
+//	query ($config_expression: String!, $repositories_cursor: String) {
+//	  viewer {
+//	    repositories(
+//	      first: 100
+//	      after: $repositories_cursor
+//	      isArchived: false
+//	      affiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER]
+//	      ownerAffiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER]
+//	    ) {
+//	      pageInfo {
+//	        endCursor
+//	        hasNextPage
+//	      }
+//	      nodes {
+//	        nameWithOwner
+//	        repositoryTopics(first: 100) {
+//	          nodes {
+//	            topic {
+//	              name
+//	            }
+//	          }
+//	        }
+//	        object(expression: $config_expression) {
+//	          ... on Blob {
+//	            text
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+//
+// Query Variables
+//
+//	{
+//	  "config_expression": "HEAD:.scm-engine.yml",
+//	  "repositories_cursor": null
+//	}
+type PeriodicEvaluationResult struct {
+	Viewer struct {
+		// Repositories contains a page of 100 repositories the token has access to
+		Repositories graphqlPageOf[PeriodicEvaluationRepositoryNode] `graphql:"repositories(first: 100, after: $repositories_cursor, isArchived: false, affiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER], ownerAffiliations: [OWNER, COLLABORATOR, ORGANIZATION_MEMBER])"`
+	} `graphql:"viewer"`
+}
+
+type PeriodicEvaluationRepositoryNode struct {
+	// NameWithOwner is the "owner/repo" identifier for a Repository in GitHub
+	NameWithOwner string `graphql:"nameWithOwner"`
+
+	// RepositoryTopics contains the topics the repository is tagged with
+	RepositoryTopics graphqlNodesOf[RepositoryTopicNode] `graphql:"repositoryTopics(first: 100)"`
+
+	// Object contains the (optional) content of the ".scm-engine.yml" file
+	// read from the repository default branch at the time of reading
+	Object *BlobNode `graphql:"object(expression: $config_expression)"`
+}
+
+// PeriodicEvaluationPullRequestsResult maps to the GraphQL query reading a page of
+// open Pull Requests in a repository, sorted by oldest update/last change first
+//
+// GraphQL query:
+//
+//	query ($owner: String!, $repo: String!, $cursor: String) {
+//	  repository(owner: $owner, name: $repo) {
+//	    pullRequests(
+//	      first: 100
+//	      after: $cursor
+//	      states: OPEN
+//	      orderBy: {field: UPDATED_AT, direction: ASC}
+//	    ) {
+//	      pageInfo {
+//	        endCursor
+//	        hasNextPage
+//	      }
+//	      nodes {
+//	        number
+//	        headRefOid
+//	        labels(first: 100) {
+//	          nodes {
+//	            name
+//	          }
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type PeriodicEvaluationPullRequestsResult struct {
+	Repository struct {
+		PullRequests graphqlPageOf[PeriodicEvaluationPullRequestNode] `graphql:"pullRequests(first: 100, after: $cursor, states: OPEN, orderBy: {field: UPDATED_AT, direction: ASC})"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type PeriodicEvaluationPullRequestNode struct {
+	Number int                       `graphql:"number"`
+	SHA    string                    `graphql:"headRefOid"`
+	Labels graphqlNodesOf[LabelNode] `graphql:"labels(first: 100)"`
+}
+
+// hasTopics checks if the repository is tagged with all of the topics
+func (node PeriodicEvaluationRepositoryNode) hasTopics(topics []string) bool {
+	for _, topic := range topics {
+		if !slices.ContainsFunc(node.RepositoryTopics.Nodes, func(n RepositoryTopicNode) bool { return n.Topic.Name == topic }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesLabels checks if the pull request has all the required labels, and none of the ignored ones
+func (node PeriodicEvaluationPullRequestNode) matchesLabels(required, ignored []string) bool {
+	labels := make([]string, 0, len(node.Labels.Nodes))
+	for _, label := range node.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
+	for _, label := range required {
+		if !slices.Contains(labels, label) {
+			return false
+		}
+	}
+
+	for _, label := range ignored {
+		if slices.Contains(labels, label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type RepositoryTopicNode struct {
+	Topic struct {
+		Name string `graphql:"name"`
+	} `graphql:"topic"`
+}
+
+type LabelNode struct {
+	Name string `graphql:"name"`
+}
+
+type BlobNode struct {
+	Blob struct {
+		Text string `graphql:"text"`
+	} `graphql:"... on Blob"`
+}
+
+type graphqlNodesOf[T any] struct {
+	Nodes []T `graphql:"nodes"`
+}
+
+type graphqlPageOf[T any] struct {
+	PageInfo struct {
+		EndCursor   *string `graphql:"endCursor"`
+		HasNextPage bool    `graphql:"hasNextPage"`
+	} `graphql:"pageInfo"`
+	Nodes []T `graphql:"nodes"`
+}
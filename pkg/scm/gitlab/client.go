@@ -57,6 +57,9 @@ var SkipPipelineUpdateIfPeriodicAndPipelineStatusIs = []string{
 // Ensure the GitLab client implements the [scm.Client]
 var _ scm.Client = (*Client)(nil)
 
+// Ensure the GitLab client can look up blob SHAs for the configuration cache
+var _ scm.BlobSHAReader = (*Client)(nil)
+
 // Client is a wrapper around the GitLab specific implementation of [scm.Client] interface
 type Client struct {
 	wrapped *go_gitlab.Client
@@ -64,18 +67,22 @@ type Client struct {
 	labels        *LabelClient
 	mergeRequests *MergeRequestClient
 	backstage     *backstage.Client
+	outOfOffice   scm.OutOfOfficeProvider
+
+	// botUsername is the username of the API token's user, looked up on first use
+	botUsername string
 
 	httpClient *http.Client // used for testing
 }
 
-// NewClient creates a new GitLab client with an optional backstage client
-func NewClient(ctx context.Context, backstageClient *backstage.Client) (*Client, error) {
+// NewClient creates a new GitLab client with an optional backstage client and out of office provider
+func NewClient(ctx context.Context, backstageClient *backstage.Client, outOfOffice scm.OutOfOfficeProvider) (*Client, error) {
 	client, err := go_gitlab.NewClient(state.Token(ctx), go_gitlab.WithBaseURL(state.BaseURL(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{wrapped: client, backstage: backstageClient}, nil
+	return &Client{wrapped: client, backstage: backstageClient, outOfOffice: outOfOffice}, nil
 }
 
 // Labels returns a client target at managing labels/tags
@@ -191,6 +198,30 @@ func (client *Client) GetProjectFiles(ctx context.Context, project string, ref *
 	return fileContents, nil
 }
 
+// GetProjectFileSHAs looks up the blob SHA of the files, without downloading their content
+func (client *Client) GetProjectFileSHAs(ctx context.Context, project string, ref *string, files []string) (map[string]string, error) {
+	var (
+		response  IncludeBlobSHAResult
+		variables = map[string]any{
+			"project": graphql.ID(project),
+			"files":   files,
+			"ref":     ref,
+		}
+	)
+
+	if err := client.newGraphQLClient(ctx).Query(ctx, &response, variables); err != nil {
+		return nil, fmt.Errorf("GraphQL query failed while trying to read the blob SHA of files [%v] for project [%s]: %w", files, project, err)
+	}
+
+	shas := make(map[string]string, len(files))
+
+	for _, blob := range response.Project.Repository.Blobs.Nodes {
+		shas[blob.Path] = blob.Oid
+	}
+
+	return shas, nil
+}
+
 // Start pipeline
 func (client *Client) Start(ctx context.Context) error {
 	ok, pattern := state.ShouldUpdatePipeline(ctx)
@@ -2,18 +2,11 @@ package gitlab
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log/slog"
-	"reflect"
-	"strings"
 
-	"github.com/expr-lang/expr"
-	"github.com/expr-lang/expr/patcher"
 	"github.com/jippi/scm-engine/pkg/config"
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
-	"github.com/jippi/scm-engine/pkg/stdlib"
 	slogctx "github.com/veqryn/slog-context"
 	"gitlab.com/gitlab-org/api/client-go/v2"
 )
@@ -26,66 +19,13 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 
 	switch action {
 	case "update_description":
-		// Use the raw MR description
-		body := evalContext.GetDescription()
+		return config.UpdateDescription(ctx, evalContext, update, step)
 
-		// Unless something else already updated the description in the Update struct
-		if update.Description != nil {
-			body = *update.Description
-		}
-
-		replacements, err := step.Get("replace")
-		if err != nil {
-			return err
-		}
-
-		replacementSlice, ok := replacements.(config.ActionStep)
-		if !ok {
-			return fmt.Errorf(`step field 'replace' must be a dictionary with string key and string values ("key": "value"), got: %T`, replacements)
-		}
-
-		replacedAnything := false
-
-		for key, script := range replacementSlice {
-			// If the replacement key do not exist; we can skip the replacement logic entirely!
-			if !strings.Contains(body, key) {
-				continue
-			}
-
-			replacedAnything = true
-
-			// Build the ExprLang VM program
-			// TODO(jippi): make this something generic/shared somewhere more central so we keep settings in sync
-			opts := make([]expr.Option, 0, len(stdlib.Functions)+5)
-			opts = append(opts, expr.AsKind(reflect.TypeFor[string]().Kind()), expr.Env(evalContext), stdlib.FunctionRenamer)
-			opts = append(opts, stdlib.Functions...)
-			opts = append(opts, expr.Patch(patcher.WithContext{Name: "ctx"}))
-
-			program, err := expr.Compile(fmt.Sprintf("%s", script), opts...)
-			if err != nil {
-				return fmt.Errorf("could not evaluate value for 'replace' key '%s': %w", key, err)
-			}
-
-			output, err := expr.Run(program, evalContext)
-			if err != nil {
-				return err
-			}
-
-			switch val := output.(type) {
-			case string:
-				body = strings.ReplaceAll(body, key, val)
-
-			default:
-				return fmt.Errorf("'replace' value for key '%s' did not return a string: %w", key, err)
-			}
-		}
-
-		// Don't update the body if there were no replacements
-		if !replacedAnything {
-			return nil
-		}
+	case "store_value":
+		return config.StoreValue(ctx, evalContext, step)
 
-		update.Description = &body
+	case "delete_stored_value":
+		return config.DeleteStoredValue(ctx, step)
 
 	case "add_label":
 		name, err := step.RequiredString("label")
@@ -123,6 +63,9 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 	case "reopen":
 		update.StateEvent = scm.Ptr("reopen")
 
+	case "rebase":
+		return config.Rebase(update, step)
+
 	case "lock_discussion":
 		update.DiscussionLocked = scm.Ptr(true)
 
@@ -151,30 +94,38 @@ func (c *Client) ApplyStep(ctx context.Context, evalContext scm.EvalContext, upd
 
 		return err
 
+	case "assign":
+		return c.Assign(ctx, evalContext, update, step)
+
+	case "set_milestone":
+		return c.SetMilestone(ctx, update, step)
+
+	case "update_title":
+		return updateTitle(ctx, evalContext, update, step)
+
+	case "mark_draft":
+		setDraft(evalContext, update, true)
+
+	case "mark_ready":
+		setDraft(evalContext, update, false)
+
 	case "assign_reviewers":
 		return c.AssignReviewers(ctx, evalContext, update, step)
 
 	case "comment":
-		message, err := step.RequiredString("message")
-		if err != nil {
-			return err
-		}
-
-		if len(message) == 0 {
-			return errors.New("step field 'message' must not be an empty string")
-		}
+		return config.Comment(ctx, evalContext, step, c)
 
-		if state.IsDryRun(ctx) {
-			slogctx.Info(ctx, "(Dry Run) Commenting on MR", slog.String("message", message))
+	case "discussion":
+		return config.Discussion(ctx, evalContext, step, c)
 
-			return nil
-		}
+	case "resolve_discussions":
+		return config.ResolveDiscussions(ctx, step, c)
 
-		_, _, err = c.wrapped.Notes.CreateMergeRequestNote(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), &gitlab.CreateMergeRequestNoteOptions{
-			Body: scm.Ptr(message),
-		})
+	case "merge":
+		return c.Merge(ctx, evalContext, step)
 
-		return err
+	case "auto_merge":
+		return c.AutoMerge(ctx, evalContext, step)
 
 	default:
 		return fmt.Errorf("GitLab client does not know how to apply action %q", action)
@@ -8,6 +8,7 @@ import (
 	"github.com/jippi/scm-engine/pkg/scm"
 	"github.com/jippi/scm-engine/pkg/state"
 	slogctx "github.com/veqryn/slog-context"
+	go_gitlab "gitlab.com/gitlab-org/api/client-go/v2"
 )
 
 func (c *Client) AssignReviewers(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
@@ -16,15 +17,13 @@ func (c *Client) AssignReviewers(ctx context.Context, evalContext scm.EvalContex
 		return err
 	}
 
-	desiredLimit, err := step.OptionalInt("limit", 1)
+	selection, err := scm.NewReviewerSelection(step)
 	if err != nil {
 		return err
 	}
 
-	mode, err := step.OptionalStringEnum("mode", "random", "random")
-	if err != nil {
-		return err
-	}
+	selection.OpenReviews = c.openReviews
+	selection.OutOfOffice = c.outOfOffice
 
 	// prevents misuse and situations where evaluate will assign reviewers endlessly
 	existingReviewers := evalContext.GetReviewers()
@@ -79,55 +78,99 @@ func (c *Client) AssignReviewers(ctx context.Context, evalContext scm.EvalContex
 		break
 	}
 
+	// skip invalid int ids before selecting, so they never take a slot or have their open reviews counted
+	validReviewers := make(scm.Actors, 0, len(eligibleReviewers))
+
+	for _, reviewer := range eligibleReviewers {
+		if reviewer.IntID() == 0 {
+			slogctx.Warn(ctx, "Invalid reviewer ID", slog.String("id", reviewer.ID))
+
+			continue
+		}
+
+		validReviewers = append(validReviewers, reviewer)
+	}
+
+	eligibleReviewers = validReviewers
+
 	if len(eligibleReviewers) == 0 {
 		slogctx.Debug(ctx, "No eligible reviewers found")
 
 		return nil
 	}
 
-	var reviewers scm.Actors
+	reviewers, err := selection.Select(ctx, eligibleReviewers)
+	if err != nil {
+		return err
+	}
+
+	if len(reviewers) == 0 {
+		slogctx.Debug(ctx, "No eligible reviewers left after exclusions")
 
-	limit := desiredLimit
-	if limit > len(eligibleReviewers) {
-		limit = len(eligibleReviewers)
+		return nil
 	}
 
-	switch mode {
-	case "random":
-		reviewers = make(scm.Actors, limit)
+	reviewerIDs := make([]int, 0, len(reviewers))
 
-		rand := state.RandomSeed(ctx)
-		perm := rand.Perm(len(eligibleReviewers))
+	for _, reviewer := range reviewers {
+		reviewerIDs = append(reviewerIDs, reviewer.IntID())
+	}
 
-		for i := 0; i < limit; i++ {
-			reviewers[i] = eligibleReviewers[perm[i]]
-		}
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Assigning MR", slog.String("source", source), slog.Int("limit", selection.Limit), slog.String("mode", selection.Mode), slog.Any("reviewers", reviewers))
 
-		break
+		return nil
 	}
 
-	reviewerIDs := make([]int, 0, len(reviewers))
+	update.AppendReviewerIDs(reviewerIDs)
 
-	for _, reviewer := range reviewers {
-		id := reviewer.IntID()
+	return nil
+}
+
+// Assign applies the 'assign' action step, assigning the author, code owners or static users to the Merge Request
+func (c *Client) Assign(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	assignees, err := scm.SelectAssignees(ctx, evalContext, step, "user_ids", func(id string) scm.Actor { return scm.Actor{ID: id} })
+	if err != nil {
+		return err
+	}
+
+	assigneeIDs := make([]int, 0, len(assignees))
+
+	for _, assignee := range assignees {
+		id := assignee.IntID()
 
 		// skip invalid int ids, this should not happen but still safeguard against it
 		if id == 0 {
-			slogctx.Warn(ctx, "Invalid reviewer ID", slog.String("id", reviewer.ID))
+			slogctx.Warn(ctx, "Invalid assignee ID", slog.String("id", assignee.ID))
 
 			continue
 		}
 
-		reviewerIDs = append(reviewerIDs, id)
+		assigneeIDs = append(assigneeIDs, id)
 	}
 
-	if state.IsDryRun(ctx) {
-		slogctx.Info(ctx, "(Dry Run) Assigning MR", slog.String("source", source), slog.Int("limit", limit), slog.String("mode", mode), slog.Any("reviewers", reviewers))
+	if len(assigneeIDs) == 0 {
+		slogctx.Debug(ctx, "No eligible assignees found")
 
 		return nil
 	}
 
-	update.AppendReviewerIDs(reviewerIDs)
+	update.AssigneeIDs = &assigneeIDs
 
 	return nil
 }
+
+// openReviews counts the open Merge Requests the actor is a reviewer on, across all projects
+func (c *Client) openReviews(ctx context.Context, actor scm.Actor) (int, error) {
+	_, resp, err := c.wrapped.MergeRequests.ListMergeRequests(&go_gitlab.ListMergeRequestsOptions{
+		ListOptions: go_gitlab.ListOptions{PerPage: 1},
+		State:       go_gitlab.Ptr("opened"),
+		Scope:       go_gitlab.Ptr("all"),
+		ReviewerID:  go_gitlab.ReviewerID(actor.IntID()),
+	}, go_gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(resp.TotalItems), nil
+}
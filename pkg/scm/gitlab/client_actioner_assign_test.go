@@ -3,6 +3,10 @@ package gitlab_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/jippi/scm-engine/pkg/config"
@@ -39,6 +43,12 @@ func (c *evalContextMock) GetDescription() string {
 	return args.String(0)
 }
 
+func (c *evalContextMock) GetTitle() string {
+	args := c.Called()
+
+	return args.String(0)
+}
+
 func (c *evalContextMock) CanUseConfigurationFileFromChangeRequest(ctx context.Context) bool {
 	args := c.Called(ctx)
 
@@ -81,6 +91,16 @@ func (c *evalContextMock) GetReviewers() scm.Actors {
 	return nil
 }
 
+func (c *evalContextMock) GetAssignees() scm.Actors {
+	args := c.Called()
+
+	if actors, ok := args.Get(0).(scm.Actors); ok {
+		return actors
+	}
+
+	return nil
+}
+
 func (c *evalContextMock) GetAuthor() scm.Actor {
 	args := c.Called()
 
@@ -101,6 +121,16 @@ func (c *evalContextMock) GetLabels() []string {
 	return nil
 }
 
+func (c *evalContextMock) GetModifiedFiles() []string {
+	args := c.Called()
+
+	if files, ok := args.Get(0).([]string); ok {
+		return files
+	}
+
+	return nil
+}
+
 func TestAssignReviewers_codeowners(t *testing.T) {
 	t.Parallel()
 
@@ -313,6 +343,50 @@ func TestAssignReviewers_static(t *testing.T) {
 	}
 }
 
+// Invalid user IDs are skipped before the open reviews are counted
+func TestAssignReviewers_leastBusySkipsInvalidIDs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu          sync.Mutex
+		reviewerIDs []string
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		reviewerIDs = append(reviewerIDs, r.URL.Query().Get("reviewer_id"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total", "0")
+		fmt.Fprint(w, `[]`)
+	}))
+	t.Cleanup(upstream.Close)
+
+	evalContext := new(evalContextMock)
+	evalContext.On("GetReviewers").Return(nil)
+
+	ctx := state.WithDryRun(t.Context(), false)
+	ctx = state.WithBaseURL(ctx, upstream.URL)
+	ctx = state.WithToken(ctx, "token")
+	ctx = state.WithRandomSeed(ctx, 1)
+
+	client, err := gitlab.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	update := &scm.UpdateMergeRequestOptions{}
+
+	err = client.AssignReviewers(ctx, evalContext, update, config.ActionStep{
+		"source":   "static",
+		"user_ids": []string{"not-a-number", "100"},
+		"mode":     "least_busy",
+		"limit":    2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, scm.Ptr([]int{100}), update.ReviewerIDs)
+	require.Equal(t, []string{"100"}, reviewerIDs)
+}
+
 func TestAssignReviewers_backstage(t *testing.T) {
 	t.Parallel()
 
@@ -357,7 +431,7 @@ func TestAssignReviewers_backstage(t *testing.T) {
 				t.Fatalf("failed to create backstage client: %v", err)
 			}
 
-			client, err := gitlab.NewClient(ctx, backstageClient)
+			client, err := gitlab.NewClient(ctx, backstageClient, nil)
 			if err != nil {
 				t.Fatalf("failed to create gitlab client: %v", err)
 			}
@@ -0,0 +1,117 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+	go_gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// autoMergeBlockingStatuses are the detailed merge statuses that won't resolve themselves by waiting for the pipeline
+var autoMergeBlockingStatuses = []DetailedMergeStatus{
+	DetailedMergeStatusConflict,
+	DetailedMergeStatusDraftStatus,
+	DetailedMergeStatusNeedRebase,
+	DetailedMergeStatusNotOpen,
+}
+
+// Merge applies the 'merge' action step, merging the Merge Request right away
+func (c *Client) Merge(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) error {
+	options, err := acceptMergeRequestOptions(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	status := detailedMergeStatus(evalContext)
+	if status != DetailedMergeStatusMergeable {
+		return fmt.Errorf("Merge Request is not mergeable, the detailed merge status is %s", status)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Merging MR", slog.Bool("squash", *options.Squash))
+
+		return nil
+	}
+
+	_, _, err = c.wrapped.MergeRequests.AcceptMergeRequest(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), options)
+
+	return err
+}
+
+// AutoMerge applies the 'auto_merge' action step, setting (or canceling) merge when the pipeline succeeds
+func (c *Client) AutoMerge(ctx context.Context, evalContext scm.EvalContext, step scm.ActionStep) error {
+	mode, err := step.OptionalStringEnum("mode", "set", "set", "cancel")
+	if err != nil {
+		return err
+	}
+
+	if mode == "cancel" {
+		if state.IsDryRun(ctx) {
+			slogctx.Info(ctx, "(Dry Run) Canceling auto-merge of MR")
+
+			return nil
+		}
+
+		_, _, err := c.wrapped.MergeRequests.CancelMergeWhenPipelineSucceeds(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)))
+
+		return err
+	}
+
+	options, err := acceptMergeRequestOptions(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	options.AutoMerge = scm.Ptr(true)
+
+	if status := detailedMergeStatus(evalContext); slices.Contains(autoMergeBlockingStatuses, status) {
+		return fmt.Errorf("Merge Request can't be set to auto-merge, the detailed merge status is %s", status)
+	}
+
+	if state.IsDryRun(ctx) {
+		slogctx.Info(ctx, "(Dry Run) Setting MR to auto-merge", slog.Bool("squash", *options.Squash))
+
+		return nil
+	}
+
+	_, _, err = c.wrapped.MergeRequests.AcceptMergeRequest(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), options)
+
+	return err
+}
+
+// acceptMergeRequestOptions reads the 'squash' and 'sha_guard' step fields.
+//
+// With 'sha_guard' (default) the merge fails if commits were pushed after the evaluated commit
+func acceptMergeRequestOptions(ctx context.Context, step scm.ActionStep) (*go_gitlab.AcceptMergeRequestOptions, error) {
+	squash, err := step.OptionalBool("squash", false)
+	if err != nil {
+		return nil, err
+	}
+
+	shaGuard, err := step.OptionalBool("sha_guard", true)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &go_gitlab.AcceptMergeRequestOptions{Squash: scm.Ptr(squash)}
+
+	if sha := state.CommitSHA(ctx); shaGuard && len(sha) > 0 {
+		options.SHA = scm.Ptr(sha)
+	}
+
+	return options, nil
+}
+
+func detailedMergeStatus(evalContext scm.EvalContext) DetailedMergeStatus {
+	glContext, ok := evalContext.(*Context)
+	if !ok || glContext.MergeRequest == nil || glContext.MergeRequest.DetailedMergeStatus == nil {
+		return DetailedMergeStatusUnchecked
+	}
+
+	return *glContext.MergeRequest.DetailedMergeStatus
+}
@@ -0,0 +1,77 @@
+package gitlab_test
+
+import (
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyStep_merge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		step    config.ActionStep
+		status  gitlab.DetailedMergeStatus
+		wantErr string
+	}{
+		{
+			name:   "merge a mergeable MR",
+			step:   config.ActionStep{"action": "merge", "squash": true},
+			status: gitlab.DetailedMergeStatusMergeable,
+		},
+		{
+			name:    "merge requires a mergeable MR",
+			step:    config.ActionStep{"action": "merge"},
+			status:  gitlab.DetailedMergeStatusCiStillRunning,
+			wantErr: "Merge Request is not mergeable, the detailed merge status is CI_STILL_RUNNING",
+		},
+		{
+			name:    "squash must be a bool",
+			step:    config.ActionStep{"action": "merge", "squash": "yes"},
+			status:  gitlab.DetailedMergeStatusMergeable,
+			wantErr: "Optional step field 'squash' must be of type bool",
+		},
+		{
+			name:   "auto_merge while the pipeline is running",
+			step:   config.ActionStep{"action": "auto_merge"},
+			status: gitlab.DetailedMergeStatusCiStillRunning,
+		},
+		{
+			name:    "auto_merge with conflicts",
+			step:    config.ActionStep{"action": "auto_merge"},
+			status:  gitlab.DetailedMergeStatusConflict,
+			wantErr: "Merge Request can't be set to auto-merge, the detailed merge status is CONFLICT",
+		},
+		{
+			name:   "cancel auto_merge",
+			step:   config.ActionStep{"action": "auto_merge", "mode": "cancel"},
+			status: gitlab.DetailedMergeStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Dry run, so nothing is sent to the (nil) GitLab client
+			ctx := state.WithCommitSHA(t.Context(), "c0ffee")
+			ctx = state.WithDryRun(ctx, true)
+
+			evalContext := &gitlab.Context{MergeRequest: &gitlab.ContextMergeRequest{DetailedMergeStatus: &tt.status}}
+
+			err := (&gitlab.Client{}).ApplyStep(ctx, evalContext, &scm.UpdateMergeRequestOptions{}, tt.step)
+			if len(tt.wantErr) > 0 {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
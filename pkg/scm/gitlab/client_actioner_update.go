@@ -0,0 +1,92 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	go_gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// draftPrefix matches the title prefixes GitLab uses to mark a Merge Request as draft
+var draftPrefix = regexp.MustCompile(`(?i)^\s*((draft:|\[draft]|\(draft\))\s*)+`)
+
+// SetMilestone applies the 'set_milestone' action step, resolving the milestone title to its ID.
+//
+// Milestones of the project and its ancestor groups are considered
+func (c *Client) SetMilestone(ctx context.Context, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	title, err := step.RequiredString("milestone")
+	if err != nil {
+		return err
+	}
+
+	milestones, _, err := c.wrapped.Milestones.ListMilestones(state.ProjectID(ctx), &go_gitlab.ListMilestonesOptions{
+		Title:            scm.Ptr(title),
+		IncludeAncestors: scm.Ptr(true),
+	}, go_gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("could not list milestones: %w", err)
+	}
+
+	if len(milestones) == 0 {
+		return fmt.Errorf("milestone %q does not exist in the project or its groups", title)
+	}
+
+	update.MilestoneID = scm.Ptr(int(milestones[0].ID))
+
+	return nil
+}
+
+// updateTitle applies the 'update_title' action step, keeping a draft Merge Request a draft
+//
+// GitLab marks a Merge Request as draft with a title prefix, so it's added back when the rendered title lacks it
+func updateTitle(ctx context.Context, evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, step scm.ActionStep) error {
+	previous := update.Title
+	current := config.CurrentTitle(evalContext, update)
+
+	if err := config.UpdateTitle(ctx, evalContext, update, step); err != nil {
+		return err
+	}
+
+	if update.Title == nil || !draftPrefix.MatchString(current) || draftPrefix.MatchString(*update.Title) {
+		return nil
+	}
+
+	title := "Draft: " + *update.Title
+
+	// Don't update the title if it wouldn't change
+	if title == current {
+		update.Title = previous
+
+		return nil
+	}
+
+	update.Title = &title
+
+	return nil
+}
+
+// setDraft applies the 'mark_draft' and 'mark_ready' action steps.
+//
+// GitLab marks a Merge Request as draft with a title prefix, so the title is updated instead
+func setDraft(evalContext scm.EvalContext, update *scm.UpdateMergeRequestOptions, draft bool) {
+	title := config.CurrentTitle(evalContext, update)
+	isDraft := draftPrefix.MatchString(title)
+
+	switch {
+	case draft && !isDraft:
+		title = "Draft: " + title
+
+	case !draft && isDraft:
+		title = draftPrefix.ReplaceAllString(title, "")
+
+	default:
+		// Already in the requested state
+		return
+	}
+
+	update.Title = &title
+}
@@ -0,0 +1,167 @@
+package gitlab_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyStep_draft(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		action string
+		title  string
+		want   *string
+	}{
+		{name: "mark_draft adds the prefix", action: "mark_draft", title: "Fix the bug", want: scm.Ptr("Draft: Fix the bug")},
+		{name: "mark_draft keeps an existing prefix", action: "mark_draft", title: "[Draft] Fix the bug", want: nil},
+		{name: "mark_ready removes the prefix", action: "mark_ready", title: "Draft: Fix the bug", want: scm.Ptr("Fix the bug")},
+		{name: "mark_ready removes repeated prefixes", action: "mark_ready", title: "(draft) DRAFT: Fix the bug", want: scm.Ptr("Fix the bug")},
+		{name: "mark_ready on a ready MR", action: "mark_ready", title: "Fix the draft", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := &gitlab.Context{MergeRequest: &gitlab.ContextMergeRequest{Title: tt.title}}
+			update := &scm.UpdateMergeRequestOptions{}
+
+			err := (&gitlab.Client{}).ApplyStep(t.Context(), evalContext, update, config.ActionStep{"action": tt.action})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, update.Title)
+		})
+	}
+}
+
+func TestApplyStep_updateTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		title    string
+		newTitle string
+		want     *string
+	}{
+		{name: "ready MR", title: "Fix the bug", newTitle: "Fix the crash", want: scm.Ptr("Fix the crash")},
+		{name: "draft MR keeps the prefix", title: "Draft: Fix the bug", newTitle: "Fix the crash", want: scm.Ptr("Draft: Fix the crash")},
+		{name: "draft MR with a prefixed title", title: "Draft: Fix the bug", newTitle: "[Draft] Fix the crash", want: scm.Ptr("[Draft] Fix the crash")},
+		{name: "draft MR with an unchanged title", title: "Draft: Fix the bug", newTitle: "Fix the bug", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := &gitlab.Context{MergeRequest: &gitlab.ContextMergeRequest{Title: tt.title}}
+			update := &scm.UpdateMergeRequestOptions{}
+
+			err := (&gitlab.Client{}).ApplyStep(t.Context(), evalContext, update, config.ActionStep{"action": "update_title", "title": tt.newTitle})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, update.Title)
+		})
+	}
+}
+
+func TestApplyStep_assign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		step      config.ActionStep
+		assignees []*gitlab.ContextUser
+		want      *[]int
+		wantErr   string
+	}{
+		{
+			name: "defaults to the author",
+			step: config.ActionStep{"action": "assign"},
+			want: &[]int{1},
+		},
+		{
+			name: "static users",
+			step: config.ActionStep{"action": "assign", "source": "static", "user_ids": []any{"2", "3"}, "limit": 2},
+			want: &[]int{2, 3},
+		},
+		{
+			name:    "limit must be at least 1",
+			step:    config.ActionStep{"action": "assign", "source": "static", "user_ids": []any{"2", "3"}, "limit": -1},
+			wantErr: "step field 'limit' must be at least 1, got -1",
+		},
+		{
+			name:    "static users are required",
+			step:    config.ActionStep{"action": "assign", "source": "static"},
+			wantErr: "Required 'step' key 'user_ids' is missing",
+		},
+		{
+			name:      "already assigned",
+			step:      config.ActionStep{"action": "assign"},
+			assignees: []*gitlab.ContextUser{{ID: "gid://gitlab/User/4"}},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evalContext := &gitlab.Context{MergeRequest: &gitlab.ContextMergeRequest{
+				Author:    &gitlab.ContextUser{ID: "gid://gitlab/User/1"},
+				Assignees: tt.assignees,
+			}}
+			update := &scm.UpdateMergeRequestOptions{}
+
+			err := (&gitlab.Client{}).ApplyStep(t.Context(), evalContext, update, tt.step)
+			if len(tt.wantErr) > 0 {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, update.AssigneeIDs)
+		})
+	}
+}
+
+func TestApplyStep_setMilestone(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("title") {
+		case "v1.2.0":
+			fmt.Fprint(w, `[{"id": 1234, "iid": 3, "title": "v1.2.0"}]`)
+
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, server.URL)
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+
+	client, err := gitlab.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	update := &scm.UpdateMergeRequestOptions{}
+
+	// The global milestone ID is used, not the IID
+	require.NoError(t, client.ApplyStep(ctx, nil, update, config.ActionStep{"action": "set_milestone", "milestone": "v1.2.0"}))
+	require.Equal(t, scm.Ptr(1234), update.MilestoneID)
+
+	err = client.ApplyStep(ctx, nil, &scm.UpdateMergeRequestOptions{}, config.ActionStep{"action": "set_milestone", "milestone": "v9"})
+	require.EqualError(t, err, `milestone "v9" does not exist in the project or its groups`)
+}
@@ -0,0 +1,84 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	go_gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// Ensure the GitLab client implements the [scm.Commenter]
+var _ scm.Commenter = (*Client)(nil)
+
+// BotUsername returns the username of the API token's user
+func (c *Client) BotUsername(ctx context.Context) (string, error) {
+	if len(c.botUsername) > 0 {
+		return c.botUsername, nil
+	}
+
+	user, _, err := c.wrapped.Users.CurrentUser(go_gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("could not read the current user: %w", err)
+	}
+
+	c.botUsername = user.Username
+
+	return c.botUsername, nil
+}
+
+func (c *Client) CreateComment(ctx context.Context, body string) error {
+	_, _, err := c.wrapped.Notes.CreateMergeRequestNote(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), &go_gitlab.CreateMergeRequestNoteOptions{
+		Body: scm.Ptr(body),
+	})
+
+	return err
+}
+
+func (c *Client) DeleteComment(ctx context.Context, id int64) error {
+	_, err := c.wrapped.Notes.DeleteMergeRequestNote(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), id)
+
+	return err
+}
+
+// ListComments returns the notes on the Merge Request, excluding system notes (e.g. "added label")
+func (c *Client) ListComments(ctx context.Context) ([]scm.Comment, error) {
+	var (
+		comments []scm.Comment
+		opts     = &go_gitlab.ListMergeRequestNotesOptions{
+			ListOptions: go_gitlab.ListOptions{PerPage: 100, Page: 1},
+		}
+	)
+
+	for {
+		notes, resp, err := c.wrapped.Notes.ListMergeRequestNotes(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range notes {
+			if note.System {
+				continue
+			}
+
+			comments = append(comments, scm.Comment{ID: note.ID, Body: note.Body, Author: note.Author.Username})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return comments, nil
+}
+
+func (c *Client) UpdateComment(ctx context.Context, id int64, body string) error {
+	_, _, err := c.wrapped.Notes.UpdateMergeRequestNote(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), id, &go_gitlab.UpdateMergeRequestNoteOptions{
+		Body: scm.Ptr(body),
+	})
+
+	return err
+}
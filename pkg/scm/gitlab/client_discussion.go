@@ -0,0 +1,194 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+	go_gitlab "gitlab.com/gitlab-org/api/client-go/v2"
+)
+
+// Ensure the GitLab client implements the [scm.Discussioner]
+var _ scm.Discussioner = (*Client)(nil)
+
+// CreateDiscussion starts a thread on the Merge Request, as a diff note when a position is provided
+func (c *Client) CreateDiscussion(ctx context.Context, body string, position *scm.DiscussionPosition) error {
+	options := &go_gitlab.CreateMergeRequestDiscussionOptions{
+		Body: scm.Ptr(body),
+	}
+
+	if position != nil {
+		// Diff notes are anchored to the current diff version of the Merge Request
+		mergeRequest, _, err := c.wrapped.MergeRequests.GetMergeRequest(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), nil, go_gitlab.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("could not read the diff refs of the Merge Request: %w", err)
+		}
+
+		diff, err := c.fileDiff(ctx, position.Path)
+		if err != nil {
+			return err
+		}
+
+		options.Position = &go_gitlab.PositionOptions{
+			BaseSHA:      scm.Ptr(mergeRequest.DiffRefs.BaseSha),
+			HeadSHA:      scm.Ptr(mergeRequest.DiffRefs.HeadSha),
+			StartSHA:     scm.Ptr(mergeRequest.DiffRefs.StartSha),
+			NewPath:      scm.Ptr(position.Path),
+			OldPath:      scm.Ptr(position.Path),
+			PositionType: scm.Ptr("file"),
+		}
+
+		if diff != nil {
+			options.Position.OldPath = scm.Ptr(diff.OldPath)
+		}
+
+		if position.Line > 0 {
+			// GitLab only accepts lines shown in the diff, and unchanged lines need their old line number too
+			oldLine, ok := 0, false
+			if diff != nil {
+				oldLine, ok = diffLine(diff.Diff, position.Line)
+			}
+
+			switch {
+			case !ok:
+				slogctx.Warn(ctx, "Line is not part of the diff, anchoring the discussion to the file instead", slog.String("file", position.Path), slog.Int("line", position.Line))
+
+			case oldLine > 0:
+				options.Position.PositionType = scm.Ptr("text")
+				options.Position.NewLine = scm.Ptr(int64(position.Line))
+				options.Position.OldLine = scm.Ptr(int64(oldLine))
+
+			default:
+				options.Position.PositionType = scm.Ptr("text")
+				options.Position.NewLine = scm.Ptr(int64(position.Line))
+			}
+		}
+	}
+
+	_, _, err := c.wrapped.Discussions.CreateMergeRequestDiscussion(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), options, go_gitlab.WithContext(ctx))
+
+	return err
+}
+
+// fileDiff returns the diff of the file in the Merge Request, or nil if the file isn't changed
+func (c *Client) fileDiff(ctx context.Context, path string) (*go_gitlab.MergeRequestDiff, error) {
+	opts := &go_gitlab.ListMergeRequestDiffsOptions{
+		ListOptions: go_gitlab.ListOptions{PerPage: 100, Page: 1},
+	}
+
+	for {
+		diffs, resp, err := c.wrapped.MergeRequests.ListMergeRequestDiffs(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), opts, go_gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("could not read the diff of the Merge Request: %w", err)
+		}
+
+		for _, diff := range diffs {
+			if diff.NewPath == path {
+				return diff, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil //nolint:nilnil // the file is not changed
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// diffLine looks up the line (in the new version of the file) in the unified diff.
+//
+// It returns the old line number of an unchanged line (0 for an added line), and whether the line is in the diff at all
+func diffLine(diff string, line int) (int, bool) {
+	var oldLine, newLine int
+
+	for _, text := range strings.Split(diff, "\n") {
+		if match := hunkHeaderRegex.FindStringSubmatch(text); match != nil {
+			oldLine, _ = strconv.Atoi(match[1])
+			newLine, _ = strconv.Atoi(match[2])
+
+			continue
+		}
+
+		// Lines before the first hunk
+		if newLine == 0 {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+"):
+			if newLine == line {
+				return 0, true
+			}
+
+			newLine++
+
+		case strings.HasPrefix(text, "-"):
+			oldLine++
+
+		case strings.HasPrefix(text, " "):
+			if newLine == line {
+				return oldLine, true
+			}
+
+			oldLine++
+			newLine++
+		}
+	}
+
+	return 0, false
+}
+
+// ListDiscussions returns the resolvable threads on the Merge Request
+func (c *Client) ListDiscussions(ctx context.Context) ([]scm.Discussion, error) {
+	var (
+		discussions []scm.Discussion
+		opts        = &go_gitlab.ListMergeRequestDiscussionsOptions{
+			ListOptions: go_gitlab.ListOptions{PerPage: 100, Page: 1},
+		}
+	)
+
+	for {
+		page, resp, err := c.wrapped.Discussions.ListMergeRequestDiscussions(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), opts, go_gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, discussion := range page {
+			if len(discussion.Notes) == 0 || !discussion.Notes[0].Resolvable {
+				continue
+			}
+
+			discussions = append(discussions, scm.Discussion{
+				ID:       discussion.ID,
+				Body:     discussion.Notes[0].Body,
+				Author:   discussion.Notes[0].Author.Username,
+				Resolved: discussion.Notes[0].Resolved,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return discussions, nil
+}
+
+func (c *Client) ResolveDiscussion(ctx context.Context, id string) error {
+	_, _, err := c.wrapped.Discussions.ResolveMergeRequestDiscussion(state.ProjectID(ctx), int64(state.MergeRequestIDInt(ctx)), id, &go_gitlab.ResolveMergeRequestDiscussionOptions{
+		Resolved: scm.Ptr(true),
+	}, go_gitlab.WithContext(ctx))
+
+	return err
+}
@@ -0,0 +1,109 @@
+package gitlab_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_discussions(t *testing.T) {
+	t.Parallel()
+
+	var created, resolved []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v4/projects/jippi/scm-engine/merge_requests/42":
+			fmt.Fprint(w, `{"iid": 42, "diff_refs": {"base_sha": "base", "head_sha": "head", "start_sha": "start"}}`)
+
+		case "GET /api/v4/projects/jippi/scm-engine/merge_requests/42/diffs":
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"old_path": "go.mod", "new_path": "go.mod", "diff": "@@ -1 +1 @@\n-go 1.25\n+go 1.26\n"},
+				{"old_path": "old.go", "new_path": "main.go", "diff": "@@ -1,5 +1,6 @@\n package main\n \n+import \"fmt\"\n func main() {\n-\told()\n+\tfmt.Println()\n }\n"},
+			})
+
+		case "POST /api/v4/projects/jippi/scm-engine/merge_requests/42/discussions":
+			var body map[string]any
+
+			json.NewDecoder(r.Body).Decode(&body)
+
+			created = append(created, body)
+
+			fmt.Fprint(w, `{"id": "abc"}`)
+
+		case "GET /api/v4/projects/jippi/scm-engine/merge_requests/42/discussions":
+			fmt.Fprint(w, `[
+				{"id": "comment", "individual_note": true, "notes": [{"body": "LGTM", "resolvable": false}]},
+				{"id": "thread", "notes": [{"body": "Please fix", "author": {"username": "scm-engine"}, "resolvable": true, "resolved": true}, {"body": "Done", "resolvable": true, "resolved": true}]}
+			]`)
+
+		case "PUT /api/v4/projects/jippi/scm-engine/merge_requests/42/discussions/thread":
+			var body map[string]any
+
+			json.NewDecoder(r.Body).Decode(&body)
+
+			resolved = append(resolved, body)
+
+			fmt.Fprint(w, `{"id": "thread"}`)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := state.WithToken(t.Context(), "token")
+	ctx = state.WithBaseURL(ctx, server.URL)
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	client, err := gitlab.NewClient(ctx, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, client.CreateDiscussion(ctx, "Hello", nil))
+
+	// An added line, an unchanged line and a line outside the diff (anchored to the file instead)
+	for _, line := range []int{3, 4, 20} {
+		require.NoError(t, client.CreateDiscussion(ctx, "Hello", &scm.DiscussionPosition{Path: "main.go", Line: line}))
+	}
+
+	position := func(extra map[string]any) map[string]any {
+		want := map[string]any{
+			"base_sha":      "base",
+			"head_sha":      "head",
+			"start_sha":     "start",
+			"new_path":      "main.go",
+			"old_path":      "old.go",
+			"position_type": "text",
+		}
+
+		maps.Copy(want, extra)
+
+		return want
+	}
+
+	require.Equal(t, []map[string]any{
+		{"body": "Hello"},
+		{"body": "Hello", "position": position(map[string]any{"new_line": float64(3)})},
+		{"body": "Hello", "position": position(map[string]any{"new_line": float64(4), "old_line": float64(3)})},
+		{"body": "Hello", "position": position(map[string]any{"position_type": "file"})},
+	}, created)
+
+	// Individual notes can't be resolved, so they are not discussions
+	discussions, err := client.ListDiscussions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []scm.Discussion{{ID: "thread", Body: "Please fix", Author: "scm-engine", Resolved: true}}, discussions)
+
+	require.NoError(t, client.ResolveDiscussion(ctx, "thread"))
+	require.Equal(t, []map[string]any{{"resolved": true}}, resolved)
+}
@@ -37,7 +37,7 @@ func newLabelClient(t *testing.T, handler http.HandlerFunc) (scm.LabelClient, *l
 	ctx = state.WithBaseURL(ctx, server.URL)
 	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
 
-	client, err := gitlab.NewClient(ctx, nil)
+	client, err := gitlab.NewClient(ctx, nil, nil)
 	require.NoError(t, err)
 
 	return client.Labels(), server
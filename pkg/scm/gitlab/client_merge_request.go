@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 
 	"github.com/hasura/go-graphql-client"
 	"github.com/jippi/scm-engine/pkg/scm"
@@ -30,22 +31,50 @@ func (client *MergeRequestClient) Update(ctx context.Context, opt *scm.UpdateMer
 		return nil, err
 	}
 
-	endpoint := fmt.Sprintf("projects/%s/merge_requests/%s", go_gitlab.PathEscape(project), state.MergeRequestID(ctx))
+	// The rebase has its own endpoint, so it's not sent with the other changes.
+	//
+	// GitLab assigns by user ID and marks drafts with a title prefix, so the GitHub
+	// flavored fields are never sent either
+	changes := *opt
+	changes.Rebase = nil
+	changes.Assignees = nil
+	changes.Draft = nil
 
-	options := []go_gitlab.RequestOptionFunc{
-		go_gitlab.WithContext(ctx),
-	}
+	var resp *go_gitlab.Response
 
-	req, err := client.client.wrapped.NewRequest(http.MethodPut, endpoint, opt, options)
-	if err != nil {
-		return nil, err
-	}
+	if !reflect.DeepEqual(changes, scm.UpdateMergeRequestOptions{}) {
+		endpoint := fmt.Sprintf("projects/%s/merge_requests/%s", go_gitlab.PathEscape(project), state.MergeRequestID(ctx))
+
+		options := []go_gitlab.RequestOptionFunc{
+			go_gitlab.WithContext(ctx),
+		}
+
+		req, err := client.client.wrapped.NewRequest(http.MethodPut, endpoint, &changes, options)
+		if err != nil {
+			return nil, err
+		}
 
-	m := new(go_gitlab.MergeRequest)
+		m := new(go_gitlab.MergeRequest)
 
-	resp, err := client.client.wrapped.Do(req, m)
+		resp, err = client.client.wrapped.Do(req, m)
+		if err != nil {
+			return convertResponse(resp), err
+		}
+	}
+
+	if opt.Rebase != nil {
+		resp, err = client.client.wrapped.MergeRequests.RebaseMergeRequest(
+			project,
+			int64(state.MergeRequestIDInt(ctx)),
+			&go_gitlab.RebaseMergeRequestOptions{SkipCI: scm.Ptr(opt.Rebase.SkipCI)},
+			go_gitlab.WithContext(ctx),
+		)
+		if err != nil {
+			return convertResponse(resp), fmt.Errorf("could not rebase Merge Request: %w", err)
+		}
+	}
 
-	return convertResponse(resp), err
+	return convertResponse(resp), nil
 }
 
 func (client *MergeRequestClient) GetRemoteConfig(ctx context.Context, filename, ref string) (io.Reader, error) {
@@ -101,6 +130,10 @@ func (client *MergeRequestClient) List(ctx context.Context, options *scm.ListMer
 			continue
 		}
 
+		if len(options.SourceBranch) > 0 && mergeRequest.SourceBranch != options.SourceBranch {
+			continue
+		}
+
 		results = append(results, scm.ListMergeRequest{
 			ID:  mergeRequest.ID,
 			SHA: *mergeRequest.DiffHeadSha,
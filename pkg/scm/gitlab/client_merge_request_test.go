@@ -0,0 +1,75 @@
+package gitlab_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRequestClient_Update_rebase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		update *scm.UpdateMergeRequestOptions
+		want   []string
+	}{
+		{
+			name:   "rebase only",
+			update: &scm.UpdateMergeRequestOptions{Rebase: &scm.RebaseOptions{SkipCI: true}},
+			want: []string{
+				`PUT /api/v4/projects/jippi/scm-engine/merge_requests/42/rebase {"skip_ci":true}`,
+			},
+		},
+		{
+			name:   "rebase after the other changes",
+			update: &scm.UpdateMergeRequestOptions{Title: scm.Ptr("Fresh"), Rebase: &scm.RebaseOptions{}},
+			want: []string{
+				`PUT /api/v4/projects/jippi/scm-engine/merge_requests/42 {"title":"Fresh"}`,
+				`PUT /api/v4/projects/jippi/scm-engine/merge_requests/42/rebase {"skip_ci":false}`,
+			},
+		},
+		{
+			name:   "no rebase",
+			update: &scm.UpdateMergeRequestOptions{Title: scm.Ptr("Fresh")},
+			want: []string{
+				`PUT /api/v4/projects/jippi/scm-engine/merge_requests/42 {"title":"Fresh"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(server.Close)
+
+			ctx := state.WithToken(t.Context(), "token")
+			ctx = state.WithBaseURL(ctx, server.URL)
+			ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+			ctx = state.WithMergeRequestID(ctx, "42")
+
+			client, err := gitlab.NewClient(ctx, nil, nil)
+			require.NoError(t, err)
+
+			_, err = client.MergeRequests().Update(ctx, tt.update)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, requests)
+		})
+	}
+}
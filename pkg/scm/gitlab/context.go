@@ -2,6 +2,10 @@ package gitlab
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/hasura/go-graphql-client"
@@ -96,6 +100,25 @@ func NewContext(ctx context.Context, baseURL, token string) (*Context, error) {
 	return evalContext, nil
 }
 
+// LoadContext reads an evaluation context snapshot written by --dump-context, so a configuration
+// can be evaluated without any network access
+func LoadContext(r io.Reader) (*Context, error) {
+	var evalContext *Context
+
+	if err := json.NewDecoder(r).Decode(&evalContext); err != nil {
+		return nil, fmt.Errorf("could not decode context snapshot: %w", err)
+	}
+
+	if evalContext == nil || evalContext.MergeRequest == nil {
+		return nil, errors.New("the context snapshot does not contain a merge request")
+	}
+
+	// Initialize null-able types
+	evalContext.ActionGroups = make(map[string]any)
+
+	return evalContext, nil
+}
+
 func (c *Context) IsValid() bool {
 	return c != nil
 }
@@ -116,6 +139,10 @@ func (c *Context) GetDescription() string {
 	return *c.MergeRequest.Description
 }
 
+func (c *Context) GetTitle() string {
+	return c.MergeRequest.Title
+}
+
 func (c *Context) CanUseConfigurationFileFromChangeRequest(ctx context.Context) bool {
 	// If the Merge Request has diverged from HEAD we can't trust the configuration
 	if c.MergeRequest.DivergedFromTargetBranch {
@@ -211,6 +238,16 @@ func (c *Context) GetReviewers() scm.Actors {
 	return actors
 }
 
+func (c *Context) GetAssignees() scm.Actors {
+	actors := make(scm.Actors, 0)
+
+	for _, assignee := range c.MergeRequest.Assignees {
+		actors.Add(assignee.ToActor())
+	}
+
+	return actors
+}
+
 func (c *Context) GetAuthor() scm.Actor {
 	return c.MergeRequest.Author.ToActor()
 }
@@ -223,3 +260,13 @@ func (c *Context) GetLabels() []string {
 
 	return labels
 }
+
+func (c *Context) GetModifiedFiles() []string {
+	files := make([]string, 0, len(c.MergeRequest.DiffStats))
+
+	for _, file := range c.MergeRequest.DiffStats {
+		files = append(files, file.Path)
+	}
+
+	return files
+}
@@ -0,0 +1,51 @@
+package gitlab_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/scm/gitlab"
+	"github.com/stretchr/testify/require"
+)
+
+// A snapshot written by --dump-context must load back into an identical context,
+// otherwise --from-context would evaluate against something GitLab never returned
+func TestLoadContext_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	committed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	sinceFirstCommit := 72 * time.Hour
+
+	original := &gitlab.Context{
+		MergeRequest: &gitlab.ContextMergeRequest{
+			Title:                "Fix the thing",
+			State:                "opened",
+			DetailedMergeStatus:  scm.Ptr(gitlab.DetailedMergeStatusMergeable),
+			Labels:               []gitlab.ContextLabel{{Title: "bug"}},
+			FirstCommit:          &gitlab.ContextCommit{CommittedDate: &committed},
+			TimeSinceFirstCommit: &sinceFirstCommit,
+		},
+		ActionGroups: map[string]any{},
+	}
+
+	raw, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	loaded, err := gitlab.LoadContext(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.Equal(t, original, loaded)
+}
+
+func TestLoadContext_rejectsInvalidSnapshots(t *testing.T) {
+	t.Parallel()
+
+	_, err := gitlab.LoadContext(strings.NewReader("not json"))
+	require.ErrorContains(t, err, "could not decode context snapshot")
+
+	_, err = gitlab.LoadContext(strings.NewReader("{}"))
+	require.EqualError(t, err, "the context snapshot does not contain a merge request")
+}
@@ -93,6 +93,12 @@ type BlobNode struct {
 	Blob string `graphql:"rawBlob"`
 }
 
+// BlobSHANode is a file in the repository, without its content
+type BlobSHANode struct {
+	Path string `graphql:"path"`
+	Oid  string `graphql:"oid"`
+}
+
 type graphqlNodesOf[T any] struct {
 	Nodes []T `graphql:"nodes"`
 }
@@ -134,3 +140,28 @@ type IncludeConfigurationRepository struct {
 	// read from the projects default branch at the time of reading
 	Blobs graphqlNodesOf[BlobNode] `graphql:"blobs(paths: $files, ref: $ref, first: 100)"`
 }
+
+// IncludeBlobSHAResult is the GraphQL response for looking up the blob SHA of
+// a list of configuration files from a project repository within GitLab
+//
+// GraphQL query:
+//
+//	query ($project: ID!, $ref: String ="HEAD", $files: [String!]!) {
+//	  project(fullPath: $project) {
+//	    repository {
+//	      blobs(paths:$files, ref: $ref, first: 100) {
+//	        nodes {
+//	          path
+//	          oid
+//	        }
+//	      }
+//	    }
+//	  }
+//	}
+type IncludeBlobSHAResult struct {
+	Project struct {
+		Repository struct {
+			Blobs graphqlNodesOf[BlobSHANode] `graphql:"blobs(paths: $files, ref: $ref, first: 100)"`
+		} `graphql:"repository"`
+	} `graphql:"project(fullPath: $project)"`
+}
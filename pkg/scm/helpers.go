@@ -12,6 +12,14 @@ func Ptr[T any](v T) *T {
 	return &v
 }
 
+// IsSameUser reports if the two logins belong to the same user
+//
+// Logins are case-insensitive, and the GitHub REST API adds a "[bot]" suffix to
+// the login of GitHub Apps which the GraphQL API leaves out
+func IsSameUser(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "[bot]"), strings.TrimSuffix(b, "[bot]"))
+}
+
 // Partially lifted from https://github.com/hmarr/codeowners/blob/main/match.go
 func FindModifiedFiles(files []string, patterns ...string) []string {
 	leftAnchoredLiteral := false
@@ -74,6 +82,13 @@ func FindModifiedFiles(files []string, patterns ...string) []string {
 	return output
 }
 
+// ValidatePattern returns an error if the gitignore-style pattern can't be used with [FindModifiedFiles]
+func ValidatePattern(pattern string) error {
+	_, err := buildPatternRegex(pattern)
+
+	return err
+}
+
 // buildPatternRegex compiles a new regexp object from a gitignore-style pattern string
 func buildPatternRegex(pattern string) (*regexp.Regexp, error) {
 	// Handle specific edge cases first
@@ -67,6 +67,22 @@ func TestFindModifiedFiles(t *testing.T) {
 	}
 }
 
+func TestValidatePattern(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, scm.ValidatePattern("docs/**/*.md"))
+	require.EqualError(t, scm.ValidatePattern("docs/***"), "pattern cannot contain three consecutive asterisks")
+	require.EqualError(t, scm.ValidatePattern(""), "empty pattern")
+}
+
+func TestIsSameUser(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, scm.IsSameUser("github-actions", "github-actions[bot]"))
+	require.True(t, scm.IsSameUser("Jippi", "jippi"))
+	require.False(t, scm.IsSameUser("jippi", "jippi-bot"))
+}
+
 func TestPtr(t *testing.T) {
 	t.Parallel()
 
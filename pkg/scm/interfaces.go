@@ -3,6 +3,7 @@ package scm
 import (
 	"context"
 	"io"
+	"time"
 )
 
 type Client interface {
@@ -16,6 +17,12 @@ type Client interface {
 	Stop(ctx context.Context, err error, allowPipelineFailure bool) error
 }
 
+// BlobSHAReader looks up the git blob SHA of files, which is much cheaper than reading their content
+type BlobSHAReader interface {
+	// GetProjectFileSHAs returns the blob SHA of each file, files that don't exist are left out
+	GetProjectFileSHAs(ctx context.Context, project string, ref *string, files []string) (map[string]string, error)
+}
+
 type LabelClient interface {
 	Create(ctx context.Context, opt *CreateLabelOptions) (*Label, *Response, error)
 	List(ctx context.Context) ([]*Label, error)
@@ -28,10 +35,35 @@ type MergeRequestClient interface {
 	Update(ctx context.Context, opt *UpdateMergeRequestOptions) (*Response, error)
 }
 
+// BotUser knows the user of the API token, which authors everything scm-engine creates
+type BotUser interface {
+	BotUsername(ctx context.Context) (string, error)
+}
+
+// Commenter manages the top-level comments on the Merge Request
+type Commenter interface {
+	BotUser
+
+	CreateComment(ctx context.Context, body string) error
+	DeleteComment(ctx context.Context, id int64) error
+	ListComments(ctx context.Context) ([]Comment, error)
+	UpdateComment(ctx context.Context, id int64, body string) error
+}
+
+// Discussioner manages the resolvable threads (GitHub: review threads) on the Merge Request
+type Discussioner interface {
+	BotUser
+
+	CreateDiscussion(ctx context.Context, body string, position *DiscussionPosition) error
+	ListDiscussions(ctx context.Context) ([]Discussion, error)
+	ResolveDiscussion(ctx context.Context, id string) error
+}
+
 type EvalContext interface {
 	AllowPipelineFailure(ctx context.Context) bool
 	CanUseConfigurationFileFromChangeRequest(ctx context.Context) bool
 	GetDescription() string
+	GetTitle() string
 	HasExecutedActionGroup(name string) bool
 	IsValid() bool
 	SetContext(ctx context.Context)
@@ -39,8 +71,16 @@ type EvalContext interface {
 	TrackActionGroupExecution(name string)
 	GetCodeOwners() Actors
 	GetReviewers() Actors
+	GetAssignees() Actors
 	GetAuthor() Actor
 	GetLabels() []string
+	GetModifiedFiles() []string
+}
+
+// OutOfOfficeProvider knows when people are unavailable to review
+type OutOfOfficeProvider interface {
+	// OutOfOffice returns the reason the actor is unavailable at the given time, or an empty string if they are available
+	OutOfOffice(ctx context.Context, actor Actor, at time.Time) (string, error)
 }
 
 type ActionStep interface {
@@ -48,8 +88,11 @@ type ActionStep interface {
 	RequiredString(name string) (string, error)
 	RequiredStringEnum(name string, values ...string) (string, error)
 	RequiredStringSlice(name string) ([]string, error)
+	OptionalBool(name string, fallback bool) (bool, error)
 	OptionalInt(name string, fallback int) (int, error)
 	OptionalString(name, fallback string) (string, error)
 	OptionalStringEnum(name string, fallback string, values ...string) (string, error)
+	OptionalStringSlice(name string) ([]string, error)
+	OptionalStringSliceMap(name string) (map[string][]string, error)
 	Get(name string) (any, error)
 }
@@ -0,0 +1,280 @@
+package scm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/state"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+// roundRobinStateKey is the project state key remembering the last reviewer picked by the round_robin mode
+const roundRobinStateKey = "round_robin_last_reviewer"
+
+// ReviewerSelection holds the provider agnostic 'assign_reviewers' options for
+// picking reviewers from a list of eligible candidates
+type ReviewerSelection struct {
+	// Mode is one of random, least_busy or round_robin
+	Mode string
+	// Limit is the maximum number of reviewers to pick
+	Limit int
+	// Exclude is a list of usernames or user IDs that must never be picked
+	Exclude []string
+	// MinTeams is the minimum number of distinct teams the picked reviewers must belong to
+	MinTeams int
+	// Teams maps a team name to its members (usernames or user IDs)
+	Teams map[string][]string
+
+	// OpenReviews returns the number of open review requests for an actor, used by the least_busy mode
+	OpenReviews func(ctx context.Context, actor Actor) (int, error)
+	// OutOfOffice is consulted to skip candidates that are unavailable (optional)
+	OutOfOffice OutOfOfficeProvider
+}
+
+// NewReviewerSelection reads the reviewer selection options from an 'assign_reviewers' step
+func NewReviewerSelection(step ActionStep) (*ReviewerSelection, error) {
+	var (
+		selection ReviewerSelection
+		err       error
+	)
+
+	if selection.Limit, err = limitFromStep(step); err != nil {
+		return nil, err
+	}
+
+	if selection.Mode, err = step.OptionalStringEnum("mode", "random", "random", "least_busy", "round_robin"); err != nil {
+		return nil, err
+	}
+
+	if selection.Exclude, err = step.OptionalStringSlice("exclude"); err != nil {
+		return nil, err
+	}
+
+	if selection.MinTeams, err = step.OptionalInt("min_teams", 0); err != nil {
+		return nil, err
+	}
+
+	if selection.Teams, err = step.OptionalStringSliceMap("teams"); err != nil {
+		return nil, err
+	}
+
+	if selection.MinTeams > selection.Limit {
+		return nil, fmt.Errorf("step field 'min_teams' (%d) must not be larger than 'limit' (%d)", selection.MinTeams, selection.Limit)
+	}
+
+	return &selection, nil
+}
+
+// limitFromStep reads the optional 'limit' step field, the max number of actors to pick (default: 1)
+func limitFromStep(step ActionStep) (int, error) {
+	limit, err := step.OptionalInt("limit", 1)
+	if err != nil {
+		return 0, err
+	}
+
+	if limit < 1 {
+		return 0, fmt.Errorf("step field 'limit' must be at least 1, got %d", limit)
+	}
+
+	return limit, nil
+}
+
+// Select picks up to Limit reviewers from the candidates, honoring the mode, exclude list and team constraint
+func (s *ReviewerSelection) Select(ctx context.Context, candidates Actors) (Actors, error) {
+	eligible := make(Actors, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		if candidate.IsAny(s.Exclude...) {
+			slogctx.Info(ctx, "Skipping reviewer", slog.String("reviewer", candidate.key()), slog.String("reason", "excluded"))
+
+			continue
+		}
+
+		if s.OutOfOffice != nil {
+			reason, err := s.OutOfOffice.OutOfOffice(ctx, candidate, time.Now())
+			if err != nil {
+				return nil, err
+			}
+
+			if len(reason) > 0 {
+				slogctx.Info(ctx, "Skipping reviewer", slog.String("reviewer", candidate.key()), slog.String("reason", "out of office: "+reason))
+
+				continue
+			}
+		}
+
+		eligible = append(eligible, candidate)
+	}
+
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+
+	ordered, err := s.order(ctx, eligible)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make(Actors, 0, min(s.Limit, len(ordered)))
+	covered := map[string]bool{}
+
+	// Prefer candidates from teams not yet covered until the team constraint is satisfied
+	if s.MinTeams > 0 {
+		for _, candidate := range ordered {
+			if len(reviewers) == s.Limit || len(covered) >= s.MinTeams {
+				break
+			}
+
+			teams := s.teamsOf(candidate)
+			if !slices.ContainsFunc(teams, func(team string) bool { return !covered[team] }) {
+				continue
+			}
+
+			for _, team := range teams {
+				covered[team] = true
+			}
+
+			reviewers.Add(candidate)
+		}
+
+		if len(covered) < s.MinTeams {
+			return nil, fmt.Errorf("could not pick reviewers from %d distinct teams, the eligible reviewers only belong to %d", s.MinTeams, len(covered))
+		}
+	}
+
+	// Fill the remaining slots in order of preference
+	for _, candidate := range ordered {
+		if len(reviewers) == s.Limit {
+			break
+		}
+
+		if !reviewers.Has(candidate) {
+			reviewers.Add(candidate)
+		}
+	}
+
+	if len(reviewers) == 0 {
+		return nil, nil
+	}
+
+	if s.Mode == "round_robin" && !state.IsDryRun(ctx) {
+		last := reviewers[len(reviewers)-1].key()
+
+		if err := state.StoreFromContext(ctx).Set(ctx, state.ProjectBucket(ctx), roundRobinStateKey, []byte(last)); err != nil {
+			return nil, fmt.Errorf("could not remember the round robin reviewer: %w", err)
+		}
+	}
+
+	return reviewers, nil
+}
+
+// order returns the candidates in order of preference for the selection mode
+func (s *ReviewerSelection) order(ctx context.Context, candidates Actors) (Actors, error) {
+	ordered := make(Actors, len(candidates))
+
+	switch s.Mode {
+	case "random":
+		for i, j := range state.RandomSeed(ctx).Perm(len(candidates)) {
+			ordered[i] = candidates[j]
+		}
+
+	case "least_busy":
+		if s.OpenReviews == nil {
+			return nil, fmt.Errorf("mode %q is not supported by this provider", s.Mode)
+		}
+
+		openReviews := make(map[string]int, len(candidates))
+
+		for _, candidate := range candidates {
+			count, err := s.OpenReviews(ctx, candidate)
+			if err != nil {
+				return nil, fmt.Errorf("could not count open reviews for %q: %w", candidate.key(), err)
+			}
+
+			openReviews[candidate.key()] = count
+		}
+
+		// Shuffle first, so candidates with the same number of open reviews take turns
+		for i, j := range state.RandomSeed(ctx).Perm(len(candidates)) {
+			ordered[i] = candidates[j]
+		}
+
+		slices.SortStableFunc(ordered, func(a, b Actor) int {
+			return cmp.Compare(openReviews[a.key()], openReviews[b.key()])
+		})
+
+	case "round_robin":
+		copy(ordered, candidates)
+
+		slices.SortFunc(ordered, func(a, b Actor) int {
+			return cmp.Compare(a.key(), b.key())
+		})
+
+		last, ok, err := state.StoreFromContext(ctx).Get(ctx, state.ProjectBucket(ctx), roundRobinStateKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the round robin reviewer: %w", err)
+		}
+
+		// Continue with the candidate after the last picked one, wrapping around at the end
+		if ok {
+			next := max(0, slices.IndexFunc(ordered, func(a Actor) bool { return a.key() > string(last) }))
+
+			ordered = append(ordered[next:], ordered[:next]...)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown reviewer selection mode %q", s.Mode)
+	}
+
+	return ordered, nil
+}
+
+// teamsOf returns the teams an actor belongs to, a team reviewer is its own team
+func (s *ReviewerSelection) teamsOf(actor Actor) []string {
+	if actor.IsTeam {
+		return []string{actor.Username}
+	}
+
+	var teams []string
+
+	for team, members := range s.Teams {
+		if actor.IsAny(members...) {
+			teams = append(teams, team)
+		}
+	}
+
+	return teams
+}
+
+// IsAny reports if the actor is identified by any of the usernames or user IDs
+func (a Actor) IsAny(identifiers ...string) bool {
+	for _, identifier := range identifiers {
+		switch identifier {
+		case "":
+			continue
+
+		case a.Username, a.ID:
+			return true
+		}
+
+		if id := a.IntID(); id > 0 && strconv.Itoa(id) == identifier {
+			return true
+		}
+	}
+
+	return false
+}
+
+// key returns a stable identifier for the actor
+func (a Actor) key() string {
+	if len(a.Username) > 0 {
+		return a.Username
+	}
+
+	return a.ID
+}
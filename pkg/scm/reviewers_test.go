@@ -0,0 +1,269 @@
+package scm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jippi/scm-engine/pkg/config"
+	"github.com/jippi/scm-engine/pkg/scm"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+var candidates = scm.Actors{
+	{Username: "alice"},
+	{Username: "bob"},
+	{Username: "charlie"},
+	{Username: "dave"},
+}
+
+func usernames(actors scm.Actors) []string {
+	result := make([]string, 0, len(actors))
+
+	for _, actor := range actors {
+		result = append(result, actor.Username)
+	}
+
+	return result
+}
+
+func selectionContext(t *testing.T, dryRun bool) context.Context {
+	t.Helper()
+
+	// The round robin memory is persisted in the state store, so every test gets its own
+	ctx := state.WithStore(t.Context(), state.NewMemoryStore())
+	ctx = state.WithProvider(ctx, "gitlab")
+	ctx = state.WithProjectID(ctx, t.Name())
+	ctx = state.WithDryRun(ctx, dryRun)
+	ctx = state.WithRandomSeed(ctx, 1)
+
+	return ctx
+}
+
+func TestNewReviewerSelection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		step    config.ActionStep
+		want    *scm.ReviewerSelection
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			step: config.ActionStep{},
+			want: &scm.ReviewerSelection{Mode: "random", Limit: 1},
+		},
+		{
+			name: "all options",
+			step: config.ActionStep{
+				"mode":      "least_busy",
+				"limit":     3,
+				"exclude":   []any{"alice"},
+				"min_teams": 2,
+				"teams":     config.ActionStep{"backend": []any{"bob"}},
+			},
+			want: &scm.ReviewerSelection{
+				Mode:     "least_busy",
+				Limit:    3,
+				Exclude:  []string{"alice"},
+				MinTeams: 2,
+				Teams:    map[string][]string{"backend": {"bob"}},
+			},
+		},
+		{
+			name:    "unknown mode",
+			step:    config.ActionStep{"mode": "nope"},
+			wantErr: "must be one of [random least_busy round_robin], got nope",
+		},
+		{
+			name:    "zero limit",
+			step:    config.ActionStep{"limit": 0},
+			wantErr: "step field 'limit' must be at least 1, got 0",
+		},
+		{
+			name:    "negative limit",
+			step:    config.ActionStep{"limit": -1},
+			wantErr: "step field 'limit' must be at least 1, got -1",
+		},
+		{
+			name:    "min_teams larger than limit",
+			step:    config.ActionStep{"limit": 1, "min_teams": 2},
+			wantErr: "step field 'min_teams' (2) must not be larger than 'limit' (1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := scm.NewReviewerSelection(tt.step)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReviewerSelection_excludes(t *testing.T) {
+	t.Parallel()
+
+	selection := &scm.ReviewerSelection{Mode: "random", Limit: 10, Exclude: []string{"alice", "42"}}
+
+	got, err := selection.Select(selectionContext(t, false), append(candidates, scm.Actor{ID: "gid://gitlab/User/42"}))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"bob", "charlie", "dave"}, usernames(got))
+
+	// Excluding everyone is not an error, there is simply nobody to assign
+	selection.Exclude = []string{"alice", "bob", "charlie", "dave"}
+
+	got, err = selection.Select(selectionContext(t, false), candidates)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestReviewerSelection_leastBusy(t *testing.T) {
+	t.Parallel()
+
+	openReviews := map[string]int{"alice": 5, "bob": 0, "charlie": 2, "dave": 9}
+
+	selection := &scm.ReviewerSelection{
+		Mode:  "least_busy",
+		Limit: 2,
+		OpenReviews: func(_ context.Context, actor scm.Actor) (int, error) {
+			return openReviews[actor.Username], nil
+		},
+	}
+
+	got, err := selection.Select(selectionContext(t, false), candidates)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", "charlie"}, usernames(got))
+
+	selection.OpenReviews = func(context.Context, scm.Actor) (int, error) {
+		return 0, errors.New("rate limited")
+	}
+
+	_, err = selection.Select(selectionContext(t, false), candidates)
+	require.ErrorContains(t, err, "rate limited")
+
+	selection.OpenReviews = nil
+
+	_, err = selection.Select(selectionContext(t, false), candidates)
+	require.ErrorContains(t, err, `mode "least_busy" is not supported by this provider`)
+}
+
+func TestReviewerSelection_roundRobin(t *testing.T) {
+	t.Parallel()
+
+	ctx := selectionContext(t, false)
+	selection := &scm.ReviewerSelection{Mode: "round_robin", Limit: 1}
+
+	var picked []string
+
+	for range 5 {
+		got, err := selection.Select(ctx, candidates)
+		require.NoError(t, err)
+
+		picked = append(picked, usernames(got)...)
+	}
+
+	require.Equal(t, []string{"alice", "bob", "charlie", "dave", "alice"}, picked)
+
+	// The rotation continues after the last pick, even when that reviewer is no longer a candidate
+	got, err := selection.Select(ctx, scm.Actors{{Username: "aaron"}, {Username: "carl"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"carl"}, usernames(got))
+}
+
+// Picking nobody must not remember a round robin reviewer
+func TestReviewerSelection_roundRobinPicksNobody(t *testing.T) {
+	t.Parallel()
+
+	ctx := selectionContext(t, false)
+
+	got, err := (&scm.ReviewerSelection{Mode: "round_robin", Limit: 0}).Select(ctx, candidates)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	// The rotation starts from the beginning
+	got, err = (&scm.ReviewerSelection{Mode: "round_robin", Limit: 1}).Select(ctx, candidates)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, usernames(got))
+}
+
+func TestReviewerSelection_roundRobinDryRunDoesNotAdvance(t *testing.T) {
+	t.Parallel()
+
+	ctx := selectionContext(t, true)
+	selection := &scm.ReviewerSelection{Mode: "round_robin", Limit: 1}
+
+	for range 2 {
+		got, err := selection.Select(ctx, candidates)
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice"}, usernames(got))
+	}
+}
+
+func TestReviewerSelection_minTeams(t *testing.T) {
+	t.Parallel()
+
+	teams := map[string][]string{
+		"backend":  {"alice", "bob"},
+		"frontend": {"charlie"},
+	}
+
+	// Round robin would pick alice and bob, both from backend
+	selection := &scm.ReviewerSelection{Mode: "round_robin", Limit: 2, MinTeams: 2, Teams: teams}
+
+	got, err := selection.Select(selectionContext(t, true), candidates)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "charlie"}, usernames(got))
+
+	// Team reviewers are their own team
+	got, err = selection.Select(selectionContext(t, true), scm.Actors{{Username: "alice"}, {Username: "bob"}, {Username: "org/docs", IsTeam: true}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "org/docs"}, usernames(got))
+
+	// The constraint can not be met when the candidates span too few teams
+	_, err = selection.Select(selectionContext(t, true), scm.Actors{{Username: "alice"}, {Username: "bob"}, {Username: "dave"}})
+	require.EqualError(t, err, "could not pick reviewers from 2 distinct teams, the eligible reviewers only belong to 1")
+}
+
+type outOfOfficeStub map[string]string
+
+func (o outOfOfficeStub) OutOfOffice(_ context.Context, actor scm.Actor, _ time.Time) (string, error) {
+	if reason, ok := o[actor.Username]; ok {
+		return reason, nil
+	}
+
+	if actor.Username == "broken" {
+		return "", errors.New("calendar unavailable")
+	}
+
+	return "", nil
+}
+
+func TestReviewerSelection_skipsOutOfOffice(t *testing.T) {
+	t.Parallel()
+
+	selection := &scm.ReviewerSelection{
+		Mode:        "round_robin",
+		Limit:       4,
+		OutOfOffice: outOfOfficeStub{"alice": "vacation", "charlie": "conference"},
+	}
+
+	got, err := selection.Select(selectionContext(t, true), candidates)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", "dave"}, usernames(got))
+
+	// An unreadable source must not silently assign people who are away
+	_, err = selection.Select(selectionContext(t, true), scm.Actors{{Username: "broken"}})
+	require.EqualError(t, err, "calendar unavailable")
+}
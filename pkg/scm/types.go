@@ -31,6 +31,8 @@ type Actor struct {
 	Username string
 	Email    *string
 	IsBot    bool
+	// IsTeam is true when the actor is a team rather than a user (GitHub only), Username is then the "org/team" slug
+	IsTeam bool
 }
 
 // IntID is a safe parser for the ID field of an Actor, returning 0 if
@@ -87,21 +89,31 @@ type LabelOptions []string
 // GitLab API docs:
 // https://docs.gitlab.com/ee/api/merge_requests.html#update-mr
 type UpdateMergeRequestOptions struct {
-	Title              *string       `json:"title,omitempty"                url:"title,omitempty"`
-	Description        *string       `json:"description,omitempty"          url:"description,omitempty"`
-	TargetBranch       *string       `json:"target_branch,omitempty"        url:"target_branch,omitempty"`
-	AssigneeID         *int          `json:"assignee_id,omitempty"          url:"assignee_id,omitempty"`
-	AssigneeIDs        *[]int        `json:"assignee_ids,omitempty"         url:"assignee_ids,omitempty"`
-	ReviewerIDs        *[]int        `json:"reviewer_ids,omitempty"         url:"reviewer_ids,omitempty"`
-	Labels             *LabelOptions `json:"labels,omitempty"               url:"labels,comma,omitempty"`
-	AddLabels          *LabelOptions `json:"add_labels,omitempty"           url:"add_labels,comma,omitempty"`
-	RemoveLabels       *LabelOptions `json:"remove_labels,omitempty"        url:"remove_labels,comma,omitempty"`
-	MilestoneID        *int          `json:"milestone_id,omitempty"         url:"milestone_id,omitempty"`
-	StateEvent         *string       `json:"state_event,omitempty"          url:"state_event,omitempty"`
-	RemoveSourceBranch *bool         `json:"remove_source_branch,omitempty" url:"remove_source_branch,omitempty"`
-	Squash             *bool         `json:"squash,omitempty"               url:"squash,omitempty"`
-	DiscussionLocked   *bool         `json:"discussion_locked,omitempty"    url:"discussion_locked,omitempty"`
-	AllowCollaboration *bool         `json:"allow_collaboration,omitempty"  url:"allow_collaboration,omitempty"`
+	Title              *string        `json:"title,omitempty"                url:"title,omitempty"`
+	Description        *string        `json:"description,omitempty"          url:"description,omitempty"`
+	TargetBranch       *string        `json:"target_branch,omitempty"        url:"target_branch,omitempty"`
+	AssigneeID         *int           `json:"assignee_id,omitempty"          url:"assignee_id,omitempty"`
+	AssigneeIDs        *[]int         `json:"assignee_ids,omitempty"         url:"assignee_ids,omitempty"`
+	ReviewerIDs        *[]int         `json:"reviewer_ids,omitempty"         url:"reviewer_ids,omitempty"`
+	Labels             *LabelOptions  `json:"labels,omitempty"               url:"labels,comma,omitempty"`
+	AddLabels          *LabelOptions  `json:"add_labels,omitempty"           url:"add_labels,comma,omitempty"`
+	RemoveLabels       *LabelOptions  `json:"remove_labels,omitempty"        url:"remove_labels,comma,omitempty"`
+	MilestoneID        *int           `json:"milestone_id,omitempty"         url:"milestone_id,omitempty"`
+	StateEvent         *string        `json:"state_event,omitempty"          url:"state_event,omitempty"`
+	RemoveSourceBranch *bool          `json:"remove_source_branch,omitempty" url:"remove_source_branch,omitempty"`
+	Squash             *bool          `json:"squash,omitempty"               url:"squash,omitempty"`
+	DiscussionLocked   *bool          `json:"discussion_locked,omitempty"    url:"discussion_locked,omitempty"`
+	AllowCollaboration *bool          `json:"allow_collaboration,omitempty"  url:"allow_collaboration,omitempty"`
+	Rebase             *RebaseOptions `json:"rebase,omitempty"               url:"-"`
+	Assignees          *[]string      `json:"assignees,omitempty"            url:"-"`
+	Draft              *bool          `json:"draft,omitempty"                url:"-"`
+}
+
+// RebaseOptions requests the source branch to be rebased on (GitLab) or updated with (GitHub) the target branch,
+// after the other changes are applied
+type RebaseOptions struct {
+	// SkipCI doesn't start a pipeline for the rebased commits (GitLab only)
+	SkipCI bool `json:"skip_ci"`
 }
 
 func (o *UpdateMergeRequestOptions) AppendReviewerIDs(reviewerIDs []int) {
@@ -112,6 +124,31 @@ func (o *UpdateMergeRequestOptions) AppendReviewerIDs(reviewerIDs []int) {
 	}
 }
 
+// Discussion is a resolvable thread on a Merge Request, the body is the body of its first note
+type Discussion struct {
+	ID       string
+	Body     string
+	Resolved bool
+
+	// Author is the username of the user who started the thread
+	Author string
+}
+
+// DiscussionPosition anchors a discussion to a changed file, and to a line in the new version of it if Line is set
+type DiscussionPosition struct {
+	Path string
+	Line int
+}
+
+// Comment is a top-level comment (GitLab: note) on a Merge Request
+type Comment struct {
+	ID   int64
+	Body string
+
+	// Author is the username of the user who wrote the comment
+	Author string
+}
+
 // ListLabelsOptions represents the available ListLabels() options.
 //
 // GitLab API docs: https://docs.gitlab.com/ee/api/labels.html#list-labels
@@ -143,6 +180,9 @@ type ListMergeRequestsOptions struct {
 	ListOptions
 	State string
 	First int
+
+	// SourceBranch limits the result to Merge Requests opened from this branch
+	SourceBranch string
 }
 
 type ListMergeRequest struct {
@@ -249,6 +289,11 @@ type EvaluationResult struct {
 
 	// Wether the evaluation rule matched positive (add label) or negative (remove label)
 	Matched bool
+
+	// Stale marks an existing label owned by a 'generate' label that is no longer generated.
+	//
+	// It's only removed from the Merge Request, the label itself (color, description, ...) is left as-is
+	Stale bool
 }
 
 func (local EvaluationResult) IsEqual(ctx context.Context, remote *Label) bool {
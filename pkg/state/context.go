@@ -30,6 +30,9 @@ const (
 	backstageURL
 	backstageToken
 	globalConfigFilePath
+	outOfOfficeFilePath
+	stateStore
+	dumpContextFilePath
 )
 
 func ProjectID(ctx context.Context) string {
@@ -64,8 +67,11 @@ func WithToken(ctx context.Context, value string) context.Context {
 	return context.WithValue(ctx, token, value)
 }
 
+// Provider returns the SCM provider ("github" or "gitlab"), or an empty string when it's not set
 func Provider(ctx context.Context) string {
-	return ctx.Value(provider).(string) //nolint:forcetypeassert
+	value, _ := ctx.Value(provider).(string)
+
+	return value
 }
 
 func StartTime(ctx context.Context) time.Time {
@@ -208,3 +214,26 @@ func WithGlobalConfigFilePath(ctx context.Context, value string) context.Context
 
 	return ctx
 }
+
+func OutOfOfficeFilePath(ctx context.Context) string {
+	return ctx.Value(outOfOfficeFilePath).(string) //nolint:forcetypeassert
+}
+
+func WithOutOfOfficeFilePath(ctx context.Context, value string) context.Context {
+	ctx = context.WithValue(ctx, outOfOfficeFilePath, value)
+
+	return ctx
+}
+
+func DumpContextFilePath(ctx context.Context) string {
+	// Only the 'evaluate' command can dump the context, so it's not an error for it to be missing
+	value, _ := ctx.Value(dumpContextFilePath).(string)
+
+	return value
+}
+
+func WithDumpContextFilePath(ctx context.Context, value string) context.Context {
+	ctx = context.WithValue(ctx, dumpContextFilePath, value)
+
+	return ctx
+}
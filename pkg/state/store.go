@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Store persists key/values across evaluations.
+//
+// Keys are grouped in buckets, for example one bucket per Merge Request, see [MergeRequestBucket]
+type Store interface {
+	// Get returns the value for key, and false if the key does not exist
+	Get(ctx context.Context, bucket, key string) ([]byte, bool, error)
+	// Set creates or replaces the value for key
+	Set(ctx context.Context, bucket, key string, value []byte) error
+	// Delete removes key, deleting a key that does not exist is not an error
+	Delete(ctx context.Context, bucket, key string) error
+	// List returns all key/values in the bucket
+	List(ctx context.Context, bucket string) (map[string][]byte, error)
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// defaultStore is used when no store has been configured, so state survives for the lifetime of the process
+var defaultStore = sync.OnceValue(func() Store { return NewMemoryStore() })
+
+// OpenStore opens a store from a DSN:
+//
+//   - "" or "memory" keeps state in memory for the lifetime of the process
+//   - "bolt://path/to/state.db" keeps state in a local BoltDB file
+func OpenStore(dsn string) (Store, error) {
+	switch {
+	case dsn == "" || dsn == "memory":
+		return NewMemoryStore(), nil
+
+	case strings.HasPrefix(dsn, "bolt://"):
+		return NewBoltStore(strings.TrimPrefix(dsn, "bolt://"))
+
+	default:
+		return nil, fmt.Errorf("unsupported state store %q, use 'memory' or 'bolt://<path>'", dsn)
+	}
+}
+
+func WithStore(ctx context.Context, store Store) context.Context {
+	return context.WithValue(ctx, stateStore, store)
+}
+
+// StoreFromContext returns the configured store, or a process wide in-memory store if none was configured
+func StoreFromContext(ctx context.Context) Store {
+	if store, ok := ctx.Value(stateStore).(Store); ok {
+		return store
+	}
+
+	return defaultStore()
+}
+
+// IsPersistentStore reports if the configured store keeps its state after the process exits
+func IsPersistentStore(ctx context.Context) bool {
+	_, memory := StoreFromContext(ctx).(*MemoryStore)
+
+	return !memory
+}
+
+// MergeRequestBucket is the bucket holding the state of the current Merge Request
+func MergeRequestBucket(ctx context.Context) string {
+	return "merge_request/" + Provider(ctx) + "/" + ProjectID(ctx) + "/" + MergeRequestID(ctx)
+}
+
+// ProjectBucket is the bucket holding the state of the current project
+func ProjectBucket(ctx context.Context) string {
+	return "project/" + Provider(ctx) + "/" + ProjectID(ctx)
+}
@@ -0,0 +1,100 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore keeps state in a local BoltDB file, so it survives restarts
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// NewBoltStore opens (or creates) the BoltDB file at path
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open state store %q: %w", path, err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Get(_ context.Context, bucket, key string) ([]byte, bool, error) {
+	var (
+		value []byte
+		found bool
+	)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		// Values are only valid for the life of the transaction
+		if raw := b.Get([]byte(key)); raw != nil {
+			value, found = slices.Clone(raw), true
+		}
+
+		return nil
+	})
+
+	return value, found, err
+}
+
+func (s *BoltStore) Set(_ context.Context, bucket, key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		// bolt treats a nil value as a missing key
+		if value == nil {
+			value = []byte{}
+		}
+
+		return b.Put([]byte(key), value)
+	})
+}
+
+func (s *BoltStore) Delete(_ context.Context, bucket, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		return b.Delete([]byte(key))
+	})
+}
+
+func (s *BoltStore) List(_ context.Context, bucket string) (map[string][]byte, error) {
+	result := map[string][]byte{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(key, value []byte) error {
+			result[string(key)] = slices.Clone(value)
+
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps state in memory, it is lost when the process exits
+type MemoryStore struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]map[string][]byte{}}
+}
+
+func (s *MemoryStore) Get(_ context.Context, bucket, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.buckets[bucket][key]
+
+	return slices.Clone(value), ok, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, bucket, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = map[string][]byte{}
+	}
+
+	s.buckets[bucket][key] = slices.Clone(value)
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.buckets[bucket], key)
+
+	return nil
+}
+
+func (s *MemoryStore) List(_ context.Context, bucket string) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]byte, len(s.buckets[bucket]))
+
+	for key, value := range maps.All(s.buckets[bucket]) {
+		result[key] = slices.Clone(value)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
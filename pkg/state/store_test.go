@@ -0,0 +1,150 @@
+package state_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) state.Store{
+		"memory": func(*testing.T) state.Store {
+			return state.NewMemoryStore()
+		},
+		"bolt": func(t *testing.T) state.Store {
+			t.Helper()
+
+			store, err := state.NewBoltStore(filepath.Join(t.TempDir(), "state.db"))
+			require.NoError(t, err)
+
+			return store
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := open(t)
+			t.Cleanup(func() { require.NoError(t, store.Close()) })
+
+			ctx := t.Context()
+
+			_, found, err := store.Get(ctx, "mr/1", "warned_at")
+			require.NoError(t, err)
+			require.False(t, found, "nothing is stored yet")
+
+			require.NoError(t, store.Set(ctx, "mr/1", "warned_at", []byte("yesterday")))
+			require.NoError(t, store.Set(ctx, "mr/1", "reminder_count", []byte("2")))
+			require.NoError(t, store.Set(ctx, "mr/2", "reminder_count", []byte("5")))
+
+			value, found, err := store.Get(ctx, "mr/1", "warned_at")
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, []byte("yesterday"), value)
+
+			// An empty value is still a value
+			require.NoError(t, store.Set(ctx, "mr/1", "empty", nil))
+
+			_, found, err = store.Get(ctx, "mr/1", "empty")
+			require.NoError(t, err)
+			require.True(t, found)
+
+			all, err := store.List(ctx, "mr/1")
+			require.NoError(t, err)
+			require.Len(t, all, 3, "buckets must not leak into each other")
+			require.Equal(t, []byte("2"), all["reminder_count"])
+
+			require.NoError(t, store.Delete(ctx, "mr/1", "warned_at"))
+			require.NoError(t, store.Delete(ctx, "mr/1", "warned_at"), "deleting a missing key is not an error")
+			require.NoError(t, store.Delete(ctx, "unknown", "warned_at"), "deleting from a missing bucket is not an error")
+
+			_, found, err = store.Get(ctx, "mr/1", "warned_at")
+			require.NoError(t, err)
+			require.False(t, found)
+
+			all, err = store.List(ctx, "unknown")
+			require.NoError(t, err)
+			require.Empty(t, all)
+		})
+	}
+}
+
+func TestBoltStore_persists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	store, err := state.NewBoltStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Set(t.Context(), "mr/1", "warned_at", []byte("yesterday")))
+	require.NoError(t, store.Close())
+
+	store, err = state.NewBoltStore(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+
+	value, found, err := store.Get(t.Context(), "mr/1", "warned_at")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []byte("yesterday"), value)
+}
+
+func TestOpenStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := state.OpenStore("")
+	require.NoError(t, err)
+	require.IsType(t, &state.MemoryStore{}, store)
+
+	store, err = state.OpenStore("memory")
+	require.NoError(t, err)
+	require.IsType(t, &state.MemoryStore{}, store)
+
+	store, err = state.OpenStore("bolt://" + filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	require.IsType(t, &state.BoltStore{}, store)
+	require.NoError(t, store.Close())
+
+	_, err = state.OpenStore("redis://localhost")
+	require.EqualError(t, err, `unsupported state store "redis://localhost", use 'memory' or 'bolt://<path>'`)
+}
+
+func TestStoreFromContext(t *testing.T) {
+	t.Parallel()
+
+	// Without a configured store, the same process wide store is used every time
+	require.Same(t, state.StoreFromContext(t.Context()), state.StoreFromContext(t.Context()))
+
+	store := state.NewMemoryStore()
+	require.Same(t, store, state.StoreFromContext(state.WithStore(t.Context(), store)))
+}
+
+func TestIsPersistentStore(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, state.IsPersistentStore(t.Context()))
+	require.False(t, state.IsPersistentStore(state.WithStore(t.Context(), state.NewMemoryStore())))
+
+	store, err := state.NewBoltStore(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	require.True(t, state.IsPersistentStore(state.WithStore(t.Context(), store)))
+}
+
+func TestBuckets(t *testing.T) {
+	t.Parallel()
+
+	ctx := state.WithProvider(t.Context(), "gitlab")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	require.Equal(t, "merge_request/gitlab/jippi/scm-engine/42", state.MergeRequestBucket(ctx))
+	require.Equal(t, "project/gitlab/jippi/scm-engine", state.ProjectBucket(ctx))
+}
@@ -0,0 +1,87 @@
+package stdlib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/jippi/scm-engine/pkg/state"
+)
+
+// StoredValue returns the value stored for key on the current Merge Request, or nil when nothing is stored.
+//
+// The context argument is injected by the expr-lang context patcher, so scripts call it as stored_value("key")
+var StoredValue = expr.Function(
+	"stored_value",
+	func(args ...any) (any, error) {
+		ctx, ok := args[0].(context.Context)
+		if !ok || ctx == nil {
+			return nil, errors.New("stored_value() requires an evaluation context")
+		}
+
+		value, _, err := GetStoredValue(ctx, args[1].(string)) //nolint:forcetypeassert
+
+		return value, err
+	},
+	new(func(context.Context, string) any),
+)
+
+// HasStoredValue returns if any value is stored for key on the current Merge Request
+var HasStoredValue = expr.Function(
+	"has_stored_value",
+	func(args ...any) (any, error) {
+		ctx, ok := args[0].(context.Context)
+		if !ok || ctx == nil {
+			return nil, errors.New("has_stored_value() requires an evaluation context")
+		}
+
+		_, found, err := GetStoredValue(ctx, args[1].(string)) //nolint:forcetypeassert
+
+		return found, err
+	},
+	new(func(context.Context, string) bool),
+)
+
+// GetStoredValue reads and decodes a value stored for key on the current Merge Request.
+//
+// Values are stored as JSON, so whole numbers are returned as int and RFC3339 timestamps as time.Time,
+// which allows scripts like 'since(stored_value("warned_at")) > duration("7d")'
+func GetStoredValue(ctx context.Context, key string) (any, bool, error) {
+	raw, found, err := state.StoreFromContext(ctx).Get(ctx, state.MergeRequestBucket(ctx), key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false, fmt.Errorf("could not decode stored value %q: %w", key, err)
+	}
+
+	switch val := value.(type) {
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < math.MaxInt32 {
+			return int(val), true, nil
+		}
+
+	case string:
+		if stamp, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return stamp, true, nil
+		}
+	}
+
+	return value, true, nil
+}
+
+// SetStoredValue encodes and stores value for key on the current Merge Request
+func SetStoredValue(ctx context.Context, key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not encode value for %q: %w", key, err)
+	}
+
+	return state.StoreFromContext(ctx).Set(ctx, state.MergeRequestBucket(ctx), key, raw)
+}
@@ -0,0 +1,90 @@
+package stdlib_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/jippi/scm-engine/pkg/state"
+	"github.com/jippi/scm-engine/pkg/stdlib"
+	"github.com/stretchr/testify/require"
+)
+
+// stateEnv mirrors the evaluation contexts, which expose the request context to script functions as 'ctx'
+type stateEnv struct {
+	Context context.Context `expr:"ctx"` //nolint:containedctx
+}
+
+func stateContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := state.WithStore(t.Context(), state.NewMemoryStore())
+	ctx = state.WithProvider(ctx, "gitlab")
+	ctx = state.WithProjectID(ctx, "jippi/scm-engine")
+	ctx = state.WithMergeRequestID(ctx, "42")
+
+	return ctx
+}
+
+func TestGetStoredValue(t *testing.T) {
+	t.Parallel()
+
+	warnedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "whole numbers are returned as int", value: 3, want: 3},
+		{name: "fractions are returned as float", value: 1.5, want: 1.5},
+		{name: "timestamps are returned as time", value: warnedAt, want: warnedAt},
+		{name: "strings", value: "hello", want: "hello"},
+		{name: "booleans", value: true, want: true},
+		{name: "lists", value: []string{"a", "b"}, want: []any{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := stateContext(t)
+
+			require.NoError(t, stdlib.SetStoredValue(ctx, "key", tt.value))
+
+			got, found, err := stdlib.GetStoredValue(ctx, "key")
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStoredValueFunctions(t *testing.T) {
+	t.Parallel()
+
+	ctx := stateContext(t)
+	env := stateEnv{Context: ctx}
+
+	run := func(script string) any {
+		program, err := expr.Compile(script, stdlib.CompileOptions(env, expr.AsAny())...)
+		require.NoError(t, err)
+
+		output, err := expr.Run(program, env)
+		require.NoError(t, err)
+
+		return output
+	}
+
+	require.Nil(t, run(`stored_value("reminder_count")`))
+	require.Equal(t, false, run(`has_stored_value("reminder_count")`))
+	require.Equal(t, 1, run(`(stored_value("reminder_count") ?? 0) + 1`))
+
+	require.NoError(t, stdlib.SetStoredValue(ctx, "reminder_count", 2))
+	require.NoError(t, stdlib.SetStoredValue(ctx, "warned_at", time.Now().Add(-8*24*time.Hour)))
+
+	require.Equal(t, true, run(`has_stored_value("reminder_count")`))
+	require.Equal(t, 3, run(`(stored_value("reminder_count") ?? 0) + 1`))
+	require.Equal(t, true, run(`since(stored_value("warned_at")) > duration("7d")`))
+}
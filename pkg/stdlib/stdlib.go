@@ -2,6 +2,7 @@ package stdlib
 
 import (
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/patcher"
 	"github.com/expr-lang/expr/patcher/value"
 )
 
@@ -26,4 +27,19 @@ var Functions = []expr.Option{
 
 	// slices.Sort + slices.Compact
 	Uniq,
+
+	// Per Merge Request state, see pkg/state.Store
+	StoredValue,
+	HasStoredValue,
+}
+
+// CompileOptions returns the expr-lang options every script is compiled with, so all
+// scripts (labels, actions, replacements, ...) see the same environment and functions
+func CompileOptions(env any, returnType expr.Option) []expr.Option {
+	opts := make([]expr.Option, 0, len(Functions)+4)
+	opts = append(opts, returnType, expr.Env(env), FunctionRenamer)
+	opts = append(opts, Functions...)
+	opts = append(opts, expr.Patch(patcher.WithContext{Name: "ctx"}))
+
+	return opts
 }
@@ -220,6 +220,10 @@ func mutateHook(b *modelgen.ModelBuild) *modelgen.ModelBuild {
 					if enum, ok := enums[fieldType]; ok {
 						fieldProperty.IsEnum = true
 						fieldProperty.Enum = enum
+
+						// The API returns null for some non-pointer enums (e.g. "reviewDecision"), and the generated
+						// UnmarshalJSON rejects the empty value, so leave it out of --dump-context snapshots
+						tags.Set(&structtag.Tag{Key: "json", Options: []string{"omitempty"}})
 					}
 
 					fieldProperty.IsCustomType = !fieldProperty.IsEnum
@@ -253,7 +257,7 @@ func mutateHook(b *modelgen.ModelBuild) *modelgen.ModelBuild {
 				Description: "Go context used to pass around configuration (do not use directly!)",
 				GoName:      "Context",
 				Type:        types.NewNamed(types.NewTypeName(0, types.NewPackage("context", "context"), "Context", nil), nil, nil),
-				Tag:         `expr:"ctx" graphql:"-"`,
+				Tag:         `expr:"ctx" graphql:"-" json:"-"`,
 			})
 		}
 